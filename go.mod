@@ -7,6 +7,7 @@ require (
 	github.com/SENERGY-Platform/device-repository v0.2.43
 	github.com/SENERGY-Platform/go-service-base/struct-logger v0.6.0
 	github.com/SENERGY-Platform/models/go v0.0.0-20260302084452-04ca9ee69c93
+	github.com/eclipse/paho.golang v0.23.0
 	github.com/eclipse/paho.mqtt.golang v1.4.3
 	github.com/google/uuid v1.6.0
 	github.com/prometheus/client_golang v1.20.5
@@ -18,6 +18,7 @@ package api
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"log"
@@ -47,6 +48,14 @@ func Start(ctx context.Context, config configuration.Config, ctrl Controller) (e
 
 	router.Handle("/metrics", h)
 
+	router.HandleFunc("GET /config", func(writer http.ResponseWriter, request *http.Request) {
+		writer.Header().Set("Content-Type", "application/json; charset=utf-8")
+		err := json.NewEncoder(writer).Encode(config.Redacted())
+		if err != nil {
+			config.GetLogger().Error("unable to encode config", "error", err)
+		}
+	})
+
 	server := &http.Server{Addr: ":" + config.ServerPort, Handler: router}
 	go func() {
 		config.GetLogger().Info("listening", "address", server.Addr)
@@ -0,0 +1,150 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package canary
+
+import (
+	"crypto/tls"
+	"errors"
+	"time"
+
+	"github.com/SENERGY-Platform/canary/pkg/configuration"
+	"github.com/google/uuid"
+)
+
+const AclAttemptWrongCredentials = "wrong_credentials"
+const AclAttemptForeignCert = "foreign_cert"
+const AclAttemptForeignSubscribe = "foreign_subscribe"
+const AclAttemptForeignPublish = "foreign_publish"
+
+// aclForeignCertExpTime limits the validity of the foreign cert, in case its revocation fails
+const aclForeignCertExpTime = time.Hour
+
+// aclPublishDropWait is the time the connector gets to drop the connection after a forbidden mqtt 3.1.1 publish
+const aclPublishDropWait = 2 * time.Second
+
+// testConnectorAcl tries operations the connector must refuse.
+// subscribe and publish attempts need a connection of the canary hub; they are skipped in persistent connection mode
+// to not disturb the persistent connection.
+func (this *Canary) testConnectorAcl(token string, info DeviceInfo, hubId string, brokers []configuration.ConnectorBroker) {
+	foreignOwnerId := this.config.ConnectorAclForeignOwnerId
+	if foreignOwnerId == "" {
+		foreignOwnerId = uuid.NewString()
+	}
+	foreignLocalId := this.config.ConnectorAclForeignDeviceLocalId
+	if foreignLocalId == "" {
+		foreignLocalId = "canary_foreign_" + uuid.NewString()
+	}
+	for _, broker := range brokers {
+		this.testAclWrongCredentials(hubId, broker)
+		if broker.UseCert {
+			this.testAclForeignCert(token, hubId, broker)
+		}
+		if !this.config.ConnectorPersistentConnection {
+			time.Sleep(this.getChangeGuaranteeDuration())
+			this.testAclForeignTopics(token, hubId, broker, foreignOwnerId, foreignLocalId)
+		}
+	}
+}
+
+func (this *Canary) testAclWrongCredentials(hubId string, broker configuration.ConnectorBroker) {
+	broker.UseCert = false
+	broker.Password = "wrong_" + uuid.NewString()
+	this.metrics.ConnectorAclCheckCount.WithLabelValues(aclLabels(broker, AclAttemptWrongCredentials)...).Inc()
+	this.expectRefusedConn(hubId, broker, nil, AclAttemptWrongCredentials)
+}
+
+func (this *Canary) testAclForeignCert(token string, hubId string, broker configuration.ConnectorBroker) {
+	this.metrics.ConnectorAclCheckCount.WithLabelValues(aclLabels(broker, AclAttemptForeignCert)...).Inc()
+	tlsConf, err := this.newTlsConfigForHub(token, uuid.NewString(), aclForeignCertExpTime)
+	if err != nil {
+		this.config.GetLogger().Error("unable to create cert for foreign hub", "error", err)
+		this.metrics.UncategorizedErr.Inc()
+		return
+	}
+	this.expectRefusedConn(hubId, broker, tlsConf, AclAttemptForeignCert)
+	cert := tlsConf.Certificates[0].Leaf
+	code, err := this.revokeThrowawayCert(token, cert)
+	if err != nil {
+		this.config.GetLogger().Error("unable to revoke cert of foreign hub", "error", err, "status-code", code, "serial", cert.SerialNumber.String())
+		this.metrics.UncategorizedErr.Inc()
+	}
+}
+
+func (this *Canary) expectRefusedConn(hubId string, broker configuration.ConnectorBroker, tlsConf *tls.Config, attempt string) {
+	conn, reasonCode, err := this.openConn(hubId, broker, tlsConf)
+	if err != nil {
+		this.config.GetLogger().Debug("forbidden connection refused", "attempt", attempt, "error", err, "reason_code", formatReasonCode(reasonCode), "broker", broker.Name)
+		return
+	}
+	conn.Disconnect()
+	this.config.GetLogger().Error("SECURITY: forbidden connection accepted", "attempt", attempt, "reason_code", formatReasonCode(reasonCode), "broker", broker.Name)
+	this.metrics.ConnectorAclViolationErr.WithLabelValues(aclLabels(broker, attempt)...).Inc()
+}
+
+// testAclForeignTopics subscribes to commands and publishes events of a device the canary does not own.
+// mqtt 3.1.1 has no negative puback; a publish is refused if the connector drops the connection after it.
+// if the connection stays established, the broker may have discarded the message silently; this publish can not be
+// evaluated and is not counted.
+func (this *Canary) testAclForeignTopics(token string, hubId string, broker configuration.ConnectorBroker, foreignOwnerId string, foreignLocalId string) {
+	conn, err := this.connect(token, hubId, broker)
+	if err != nil {
+		return
+	}
+	defer conn.Disconnect()
+	conn.ExpectLoss()
+
+	cmdTopic := "command/" + foreignLocalId + "/+"
+	eventTopic := "event/" + foreignLocalId + "/sensor"
+	if this.config.TopicsWithOwner {
+		cmdTopic = "command/" + foreignOwnerId + "/" + foreignLocalId + "/+"
+		eventTopic = "event/" + foreignOwnerId + "/" + foreignLocalId + "/sensor"
+	}
+
+	this.metrics.ConnectorAclCheckCount.WithLabelValues(aclLabels(broker, AclAttemptForeignSubscribe)...).Inc()
+	reasonCode, err := conn.Subscribe(cmdTopic, 1, func(topic string, payload []byte) {})
+	if err != nil {
+		this.config.GetLogger().Debug("forbidden subscription refused", "topic", cmdTopic, "error", err, "reason_code", formatReasonCode(reasonCode), "broker", broker.Name)
+	} else {
+		this.config.GetLogger().Error("SECURITY: forbidden subscription accepted", "topic", cmdTopic, "reason_code", formatReasonCode(reasonCode), "broker", broker.Name)
+		this.metrics.ConnectorAclViolationErr.WithLabelValues(aclLabels(broker, AclAttemptForeignSubscribe)...).Inc()
+	}
+
+	lostCount := conn.LostCount()
+	reasonCode, err = conn.Publish(eventTopic, 1, []byte(`{}`))
+	if err == nil && reasonCode == NoReasonCode {
+		time.Sleep(aclPublishDropWait)
+		if conn.LostCount() > lostCount {
+			err = errors.New("connection dropped after publish")
+		}
+	}
+	switch {
+	case err != nil:
+		this.metrics.ConnectorAclCheckCount.WithLabelValues(aclLabels(broker, AclAttemptForeignPublish)...).Inc()
+		this.config.GetLogger().Debug("forbidden publish refused", "topic", eventTopic, "error", err, "reason_code", formatReasonCode(reasonCode), "broker", broker.Name)
+	case reasonCode == NoReasonCode:
+		this.config.GetLogger().Info("forbidden publish can not be evaluated: no reason code and connection not dropped", "topic", eventTopic, "broker", broker.Name)
+	default:
+		this.metrics.ConnectorAclCheckCount.WithLabelValues(aclLabels(broker, AclAttemptForeignPublish)...).Inc()
+		this.config.GetLogger().Error("SECURITY: forbidden publish accepted", "topic", eventTopic, "reason_code", formatReasonCode(reasonCode), "broker", broker.Name)
+		this.metrics.ConnectorAclViolationErr.WithLabelValues(aclLabels(broker, AclAttemptForeignPublish)...).Inc()
+	}
+}
+
+// aclLabels returns the values for metrics.ConnectorAclLabels
+func aclLabels(broker configuration.ConnectorBroker, attempt string) []string {
+	return append(brokerLabels(broker), attempt)
+}
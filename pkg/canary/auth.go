@@ -22,11 +22,66 @@ import (
 	"io"
 	"net/http"
 	"net/url"
-	"strings"
 	"time"
 )
 
-func (this *Canary) login() (token string, refreshToken string, err error) {
+// tokens are refreshed if they expire within this duration, to ensure that they stay valid for a whole phase of a test run.
+// runs take longer than the margin; long runs get a fresh token for every phase with phaseToken().
+const tokenRefreshMargin = time.Minute
+
+const GrantTypePassword = "password"
+const GrantTypeClientCredentials = "client_credentials"
+
+// getToken returns a valid access token as authorization header value.
+// the token is cached and refreshed with its refresh-token. a password login is only used as fallback.
+func (this *Canary) getToken() (token string, err error) {
+	this.tokenMux.Lock()
+	defer this.tokenMux.Unlock()
+	if this.token != nil && this.token.accessTokenValidFor(tokenRefreshMargin) {
+		return this.token.Bearer(), nil
+	}
+	if this.token != nil && this.token.refreshTokenValidFor(tokenRefreshMargin) {
+		refreshed, err := this.refresh(*this.token)
+		if err == nil {
+			this.token = &refreshed
+			return this.token.Bearer(), nil
+		}
+		this.config.GetLogger().Warn("unable to refresh token, fallback to password login", "error", err)
+	}
+	if this.token != nil && this.token.refreshTokenValidFor(0) {
+		_ = this.logout(*this.token)
+	}
+	this.token = nil
+	newToken, err := this.login()
+	if err != nil {
+		return "", err
+	}
+	this.token = &newToken
+	return this.token.Bearer(), nil
+}
+
+// phaseToken returns a token that is valid for the next phase of a test run.
+// if no token can be fetched, the previous token is returned and the failing calls are counted by the phase.
+func (this *Canary) phaseToken(previous string) string {
+	token, err := this.getToken()
+	if err != nil {
+		return previous
+	}
+	return token
+}
+
+// logoutCachedToken ends the session of the cached token
+func (this *Canary) logoutCachedToken() {
+	this.tokenMux.Lock()
+	defer this.tokenMux.Unlock()
+	if this.token != nil {
+		_ = this.logout(*this.token)
+		this.token = nil
+	}
+}
+
+// login is the fallback of getToken; the auth check uses requestLoginToken() with its own metrics
+func (this *Canary) login() (token OpenidToken, err error) {
 	this.metrics.AuthCount.Inc()
 	defer func() {
 		if err != nil {
@@ -34,48 +89,95 @@ func (this *Canary) login() (token string, refreshToken string, err error) {
 			this.metrics.AuthErr.Inc()
 		}
 	}()
-	client := http.Client{
-		Timeout: 5 * time.Second,
+	start := time.Now()
+	token, err = this.requestLoginToken()
+	this.metrics.AuthLatencyMs.Set(float64(time.Since(start).Milliseconds()))
+	return token, err
+}
+
+// requestLoginToken requests a new session with the configured grant type
+func (this *Canary) requestLoginToken() (token OpenidToken, err error) {
+	values := url.Values{}
+	switch this.config.AuthGrantType {
+	case "", GrantTypePassword:
+		values.Set("grant_type", GrantTypePassword)
+		values.Set("username", this.config.AuthUsername)
+		values.Set("password", this.config.AuthPassword)
+	case GrantTypeClientCredentials:
+		values.Set("grant_type", GrantTypeClientCredentials)
+	default:
+		return token, errors.New("unknown auth grant type: " + this.config.AuthGrantType)
 	}
+	return this.requestToken(values)
+}
+
+func (this *Canary) refresh(token OpenidToken) (result OpenidToken, err error) {
+	this.metrics.AuthRefreshCount.Inc()
+	defer func() {
+		if err != nil {
+			this.config.GetLogger().Error("ERROR: refresh()", "error", err)
+			this.metrics.AuthRefreshErr.Inc()
+		}
+	}()
 	start := time.Now()
-	var resp *http.Response
-	resp, err = client.PostForm(this.config.AuthEndpoint+"/auth/realms/master/protocol/openid-connect/token", url.Values{
-		"client_id":  {this.config.AuthClientId},
-		"username":   {this.config.AuthUsername},
-		"password":   {this.config.AuthPassword},
-		"grant_type": {"password"},
+	result, err = this.requestToken(url.Values{
+		"refresh_token": {token.RefreshToken},
+		"grant_type":    {"refresh_token"},
 	})
-	this.metrics.AuthLatencyMs.Set(float64(time.Since(start).Milliseconds()))
+	this.metrics.AuthRefreshLatencyMs.Set(float64(time.Since(start).Milliseconds()))
+	return result, err
+}
+
+func (this *Canary) requestToken(values url.Values) (token OpenidToken, err error) {
+	oidc, err := this.getOpenidConfiguration()
 	if err != nil {
-		return token, refreshToken, err
+		return token, err
 	}
+	client := http.Client{
+		Timeout: 5 * time.Second,
+	}
+	requestTime := time.Now()
+	resp, err := client.PostForm(oidc.TokenEndpoint, this.withClientCredentials(values))
+	if err != nil {
+		return token, err
+	}
+	defer resp.Body.Close()
 	if resp.StatusCode >= 300 {
 		b, _ := io.ReadAll(resp.Body)
 		err = errors.New(resp.Status + ": " + string(b))
-		return
+		return token, err
 	}
-
-	temp := OpenidToken{}
-
-	err = json.NewDecoder(resp.Body).Decode(&temp)
-	token = "Bearer " + temp.AccessToken
-	refreshToken = temp.RefreshToken
-	return
+	err = json.NewDecoder(resp.Body).Decode(&token)
+	token.RequestTime = requestTime
+	return token, err
 }
 
-func (this *Canary) logout(token string, refreshToken string) (err error) {
+func (this *Canary) logout(token OpenidToken) (err error) {
+	this.metrics.AuthLogoutCount.Inc()
+	defer func() {
+		if err != nil {
+			this.config.GetLogger().Error("ERROR: logout()", "error", err)
+			this.metrics.AuthLogoutErr.Inc()
+		}
+	}()
+	oidc, err := this.getOpenidConfiguration()
+	if err != nil {
+		return err
+	}
 	client := http.Client{
 		Timeout: 5 * time.Second,
 	}
+	start := time.Now()
 	var resp *http.Response
-	resp, err = client.PostForm(this.config.AuthEndpoint+"/auth/realms/master/protocol/openid-connect/logout", url.Values{
-		"client_id":     {this.config.AuthClientId},
-		"refresh_token": {refreshToken},
-		"id_token_hint": {strings.TrimPrefix(token, "Bearer ")},
-	})
+	resp, err = client.PostForm(oidc.EndSessionEndpoint, this.withClientCredentials(url.Values{
+		"refresh_token": {token.RefreshToken},
+		"id_token_hint": {token.AccessToken},
+	}))
+	this.metrics.AuthLogoutLatencyMs.Set(float64(time.Since(start).Milliseconds()))
 	if err != nil {
 		return err
 	}
+	defer resp.Body.Close()
 	if resp.StatusCode >= 300 {
 		b, _ := io.ReadAll(resp.Body)
 		err = errors.New(resp.Status + ": " + string(b))
@@ -84,6 +186,83 @@ func (this *Canary) logout(token string, refreshToken string) (err error) {
 	return
 }
 
+// withClientCredentials adds the client id and, if configured, the client secret to values
+func (this *Canary) withClientCredentials(values url.Values) url.Values {
+	values.Set("client_id", this.config.AuthClientId)
+	if this.config.AuthClientSecret != "" {
+		values.Set("client_secret", this.config.AuthClientSecret)
+	}
+	return values
+}
+
+type OpenidConfiguration struct {
+	Issuer             string `json:"issuer"`
+	TokenEndpoint      string `json:"token_endpoint"`
+	EndSessionEndpoint string `json:"end_session_endpoint"`
+	JwksUri            string `json:"jwks_uri"`
+}
+
+// getOpenidConfiguration returns the endpoints of the configured realm.
+// if AuthUseDiscovery is set, the endpoints are read (once) from the realms .well-known/openid-configuration.
+// otherwise they are derived from AuthEndpoint, AuthRealm, AuthTokenPath and AuthLogoutPath.
+func (this *Canary) getOpenidConfiguration() (result OpenidConfiguration, err error) {
+	this.oidcMux.Lock()
+	defer this.oidcMux.Unlock()
+	if this.oidc != nil {
+		return *this.oidc, nil
+	}
+	realm := this.config.AuthRealm
+	if realm == "" {
+		realm = "master"
+	}
+	issuer := this.config.AuthEndpoint + "/auth/realms/" + url.PathEscape(realm)
+	if this.config.AuthUseDiscovery {
+		result, err = this.discoverOpenidConfiguration(issuer + "/.well-known/openid-configuration")
+		if err != nil {
+			this.config.GetLogger().Error("unable to discover openid configuration", "error", err)
+			return result, err
+		}
+	} else {
+		result = OpenidConfiguration{
+			Issuer:             issuer,
+			TokenEndpoint:      issuer + "/protocol/openid-connect/token",
+			EndSessionEndpoint: issuer + "/protocol/openid-connect/logout",
+			JwksUri:            issuer + "/protocol/openid-connect/certs",
+		}
+	}
+	if this.config.AuthTokenPath != "" {
+		result.TokenEndpoint = this.config.AuthEndpoint + this.config.AuthTokenPath
+	}
+	if this.config.AuthLogoutPath != "" {
+		result.EndSessionEndpoint = this.config.AuthEndpoint + this.config.AuthLogoutPath
+	}
+	this.oidc = &result
+	return result, nil
+}
+
+func (this *Canary) discoverOpenidConfiguration(endpoint string) (result OpenidConfiguration, err error) {
+	client := http.Client{
+		Timeout: 5 * time.Second,
+	}
+	resp, err := client.Get(endpoint)
+	if err != nil {
+		return result, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		b, _ := io.ReadAll(resp.Body)
+		return result, errors.New(resp.Status + ": " + string(b))
+	}
+	err = json.NewDecoder(resp.Body).Decode(&result)
+	if err != nil {
+		return result, err
+	}
+	if result.TokenEndpoint == "" {
+		return result, errors.New("missing token_endpoint in openid configuration")
+	}
+	return result, nil
+}
+
 type OpenidToken struct {
 	AccessToken      string    `json:"access_token"`
 	ExpiresIn        float64   `json:"expires_in"`
@@ -92,3 +271,22 @@ type OpenidToken struct {
 	TokenType        string    `json:"token_type"`
 	RequestTime      time.Time `json:"-"`
 }
+
+func (this OpenidToken) Bearer() string {
+	return "Bearer " + this.AccessToken
+}
+
+func (this OpenidToken) accessTokenValidFor(d time.Duration) bool {
+	return this.AccessToken != "" && time.Now().Add(d).Before(this.RequestTime.Add(time.Duration(this.ExpiresIn*float64(time.Second))))
+}
+
+// a RefreshExpiresIn of 0 is used by keycloak for offline tokens, which do not expire
+func (this OpenidToken) refreshTokenValidFor(d time.Duration) bool {
+	if this.RefreshToken == "" {
+		return false
+	}
+	if this.RefreshExpiresIn == 0 {
+		return true
+	}
+	return time.Now().Add(d).Before(this.RequestTime.Add(time.Duration(this.RefreshExpiresIn * float64(time.Second))))
+}
@@ -0,0 +1,166 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package canary
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// testAuth uses its own session (independent of the cached token) to
+// validate the issued access token and to check that it is rejected after logout
+func (this *Canary) testAuth(wg *sync.WaitGroup) {
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+
+		token, err := this.checkLogin()
+		if err != nil {
+			return
+		}
+
+		this.validateToken(token)
+
+		if token.RefreshToken == "" {
+			//no session to logout (e.g. client_credentials grant)
+			return
+		}
+
+		accepted, err := this.isTokenAccepted(token)
+		if err != nil || !accepted {
+			if err == nil {
+				this.metrics.AuthRevocationCheckErr.Inc()
+				this.config.GetLogger().Error("valid token rejected by platform api")
+			}
+			_ = this.logout(token)
+			return
+		}
+
+		err = this.logout(token)
+		if err != nil {
+			return
+		}
+
+		time.Sleep(this.getChangeGuaranteeDuration())
+
+		accepted, err = this.isTokenAccepted(token)
+		if err != nil {
+			return
+		}
+		if accepted {
+			this.metrics.UnexpectedRevokedTokenAcceptedErr.Inc()
+			this.config.GetLogger().Error("SECURITY: revoked token accepted by platform api")
+		}
+	}()
+}
+
+func (this *Canary) checkLogin() (token OpenidToken, err error) {
+	this.metrics.AuthCheckLoginCount.Inc()
+	start := time.Now()
+	token, err = this.requestLoginToken()
+	this.metrics.AuthCheckLoginLatencyMs.Set(float64(time.Since(start).Milliseconds()))
+	if err != nil {
+		this.config.GetLogger().Error("unable to login for auth check", "error", err)
+		this.metrics.AuthCheckLoginErr.Inc()
+	}
+	return token, err
+}
+
+func (this *Canary) validateToken(token OpenidToken) {
+	this.metrics.AuthTokenValidationCount.Inc()
+	oidc, err := this.getOpenidConfiguration()
+	if err != nil {
+		this.metrics.AuthTokenValidationErr.Inc()
+		return
+	}
+	keys, err := this.getJwks(oidc.JwksUri)
+	if err != nil {
+		this.metrics.AuthTokenValidationErr.Inc()
+		this.config.GetLogger().Error("unable to get jwks", "error", err)
+		return
+	}
+	claims, err := verifyJwt(token.AccessToken, keys)
+	if err != nil {
+		this.metrics.AuthTokenValidationErr.Inc()
+		this.config.GetLogger().Error("invalid access token", "error", err)
+		return
+	}
+	if iat, ok := claims["iat"].(float64); ok {
+		//positive values: local clock is ahead of the auth server clock
+		this.metrics.AuthTokenClockSkewMs.Set(float64(token.RequestTime.Sub(time.UnixMilli(int64(iat * 1000))).Milliseconds()))
+	}
+	err = checkJwtClaims(claims, oidc.Issuer, this.config.AuthClientId, this.config.AuthExpectedRoles, this.config.AuthExpectedClaims, time.Now())
+	if err != nil {
+		this.metrics.AuthTokenValidationErr.Inc()
+		this.config.GetLogger().Error("unexpected access token claims", "error", err)
+		return
+	}
+}
+
+func (this *Canary) getJwks(endpoint string) (result JsonWebKeySet, err error) {
+	client := http.Client{
+		Timeout: 5 * time.Second,
+	}
+	resp, err := client.Get(endpoint)
+	if err != nil {
+		return result, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		b, _ := io.ReadAll(resp.Body)
+		return result, errors.New(resp.Status + ": " + string(b))
+	}
+	err = json.NewDecoder(resp.Body).Decode(&result)
+	return result, err
+}
+
+// isTokenAccepted requests the device-repository with token.
+// returns false if the api responds with 401 Unauthorized.
+func (this *Canary) isTokenAccepted(token OpenidToken) (accepted bool, err error) {
+	this.metrics.AuthRevocationCheckCount.Inc()
+	req, err := http.NewRequest(http.MethodGet, this.config.DeviceRepositoryUrl+"/devices?limit=1", nil)
+	if err != nil {
+		this.metrics.UncategorizedErr.Inc()
+		return false, err
+	}
+	req.Header.Set("Authorization", token.Bearer())
+	client := http.Client{
+		Timeout: 5 * time.Second,
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		this.metrics.AuthRevocationCheckErr.Inc()
+		this.config.GetLogger().Error("unable to check token acceptance", "error", err)
+		return false, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.ReadAll(resp.Body)
+	if resp.StatusCode == http.StatusUnauthorized {
+		return false, nil
+	}
+	if resp.StatusCode >= 300 {
+		err = errors.New("unexpected response: " + resp.Status)
+		this.metrics.AuthRevocationCheckErr.Inc()
+		this.config.GetLogger().Error("unable to check token acceptance", "error", err)
+		return false, err
+	}
+	return true, nil
+}
@@ -0,0 +1,147 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package canary
+
+import (
+	"fmt"
+	"math/rand"
+	"strconv"
+	"time"
+
+	"github.com/SENERGY-Platform/canary/pkg/devicemetadata"
+)
+
+// testBurst publishes ConnectorBurstSize sequence-numbered sensor events with ConnectorBurstRate events per second
+// and checks the stored events for loss, duplicates and reordering with the historic data query
+func (this *Canary) testBurst(token string, info DeviceInfo, conn Conn) {
+	interval := this.config.GetConnectorBurstInterval()
+	if interval <= 0 {
+		this.config.GetLogger().Error("invalid connector_burst_rate", "rate", this.config.ConnectorBurstRate)
+		this.metrics.UncategorizedErr.Inc()
+		return
+	}
+	this.metrics.DeviceRepoRequestCount.Inc()
+	start := time.Now()
+	dt, err, _ := this.devicerepo.ReadDeviceType(info.DeviceTypeId, token)
+	this.metrics.DeviceRepoRequestLatencyMs.Set(float64(time.Since(start).Milliseconds()))
+	if err != nil {
+		this.metrics.DeviceRepoRequestErr.Inc()
+		this.config.GetLogger().Error("unable to read device-type", "error", err)
+		return
+	}
+	serviceId := ""
+	for _, s := range dt.Services {
+		if s.LocalId == devicemetadata.SensorServiceLocalId {
+			serviceId = s.Id
+			break
+		}
+	}
+
+	this.metrics.BurstCheckCount.Inc()
+
+	//the sequence number is encoded as offset to a random base, to distinguish the burst from other sensor values
+	base := rand.Intn(1_000_000_000)
+	qos := this.config.GetConnectorQosLevels()[0]
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	published := []int{}
+	start = time.Now()
+	for seq := 0; seq < this.config.ConnectorBurstSize; seq++ {
+		if seq > 0 {
+			<-ticker.C
+		}
+		err = this.publishEvent(info, conn, devicemetadata.SensorServiceLocalId, strconv.Itoa(base+seq), qos)
+		if err == nil {
+			published = append(published, seq)
+		}
+	}
+
+	time.Sleep(this.getChangeGuaranteeDuration())
+
+	result, err := this.queryHistoricData(token, []QueriesRequestElement{{
+		DeviceId:  info.Id,
+		ServiceId: serviceId,
+		Time: QueriesRequestElementTime{
+			Start: start.Add(-time.Second).UTC().Format(time.RFC3339Nano),
+			End:   time.Now().Add(time.Second).UTC().Format(time.RFC3339Nano),
+		},
+		Limit:            2 * this.config.ConnectorBurstSize,
+		Columns:          []QueriesRequestElementColumn{{Name: "value"}},
+		OrderColumnIndex: 0,
+		OrderDirection:   "asc",
+	}})
+	if err != nil || len(result) != 1 {
+		this.metrics.BurstQueryErr.Inc()
+		this.config.GetLogger().Error("unable to query burst events", "error", err, "count", len(result))
+		return
+	}
+
+	stats := analyzeBurst(result[0], base, published)
+	this.metrics.BurstLostMessageCount.Add(float64(stats.lost))
+	this.metrics.BurstDuplicatedMessageCount.Add(float64(stats.duplicated))
+	this.metrics.BurstOutOfOrderMessageCount.Add(float64(stats.outOfOrder))
+	if stats.lost > 0 || stats.duplicated > 0 || stats.outOfOrder > 0 {
+		this.config.GetLogger().Error("unexpected burst events", "published", len(published), "lost", stats.lost, "duplicated", stats.duplicated, "out_of_order", stats.outOfOrder)
+	}
+}
+
+type burstStats struct {
+	lost       int
+	duplicated int
+	outOfOrder int
+}
+
+// analyzeBurst compares the stored burst events with the published sequence numbers.
+// rows are expected in ascending time order; values outside the published sequence are ignored.
+// events with the same time as the highest sequence number so far are not counted as out of order.
+func analyzeBurst(rows HistoricRows, base int, published []int) (result burstStats) {
+	expected := map[int]bool{}
+	for _, seq := range published {
+		expected[seq] = true
+	}
+	seen := map[int]bool{}
+	maxSeq := -1
+	var maxSeqTime time.Time
+	for _, row := range rows {
+		if len(row) < 2 {
+			continue
+		}
+		value, ok := row[1].(float64)
+		if !ok {
+			continue
+		}
+		seq := int(value) - base
+		if !expected[seq] {
+			continue
+		}
+		if seen[seq] {
+			result.duplicated++
+			continue
+		}
+		seen[seq] = true
+		t, _ := time.Parse(time.RFC3339Nano, fmt.Sprint(row[0]))
+		if seq < maxSeq && t.After(maxSeqTime) {
+			result.outOfOrder++
+		}
+		if seq > maxSeq {
+			maxSeq = seq
+			maxSeqTime = t
+		}
+	}
+	result.lost = len(expected) - len(seen)
+	return result
+}
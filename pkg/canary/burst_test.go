@@ -0,0 +1,43 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package canary
+
+import "testing"
+
+func TestAnalyzeBurst(t *testing.T) {
+	base := 1000
+	rows := HistoricRows{
+		{"2026-01-01T00:00:00.000Z", 1000.0},
+		{"2026-01-01T00:00:00.100Z", 1002.0},
+		{"2026-01-01T00:00:00.200Z", 1001.0},
+		{"2026-01-01T00:00:00.200Z", 1001.0},
+		{"2026-01-01T00:00:00.300Z", 1004.0},
+		{"2026-01-01T00:00:00.300Z", 1003.0},
+		{"2026-01-01T00:00:00.400Z", 42.0},
+	}
+	stats := analyzeBurst(rows, base, []int{0, 1, 2, 3, 4, 5})
+	if stats.lost != 1 {
+		t.Error("unexpected lost count", stats.lost)
+	}
+	if stats.duplicated != 1 {
+		t.Error("unexpected duplicated count", stats.duplicated)
+	}
+	//1003 has the same time as 1004 and is not counted
+	if stats.outOfOrder != 1 {
+		t.Error("unexpected out of order count", stats.outOfOrder)
+	}
+}
@@ -0,0 +1,81 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package canary
+
+import (
+	"context"
+	"net/http"
+	"sync"
+
+	"github.com/SENERGY-Platform/canary/pkg/configuration"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Canaries runs one Canary per configured environment and identity.
+// all metrics are collected in one registry and labeled with the environment and identity name.
+type Canaries struct {
+	config          configuration.Config
+	reg             *prometheus.Registry
+	promHttpHandler http.Handler
+	canaries        []*Canary
+}
+
+func NewCanaries(ctx context.Context, wg *sync.WaitGroup, config configuration.Config) (result *Canaries, err error) {
+	result = &Canaries{
+		config: config,
+		reg:    prometheus.NewRegistry(),
+	}
+	for _, envConfig := range config.GetEnvironments() {
+		for _, identityConfig := range envConfig.GetIdentities() {
+			reg := prometheus.WrapRegistererWith(prometheus.Labels{
+				"environment": identityConfig.EnvironmentName,
+				"identity":    identityConfig.IdentityName,
+			}, result.reg)
+			canary, err := New(ctx, wg, identityConfig, reg)
+			if err != nil {
+				return result, err
+			}
+			result.canaries = append(result.canaries, canary)
+		}
+	}
+	return result, nil
+}
+
+func (this *Canaries) GetMetricsHandler() (h http.Handler, err error) {
+	return this, nil
+}
+
+func (this *Canaries) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
+	this.config.GetLogger().Info("request", "method", request.Method, "url", request.URL, "remote_addr", request.RemoteAddr)
+	if this.promHttpHandler == nil {
+		this.promHttpHandler = promhttp.HandlerFor(
+			this.reg,
+			promhttp.HandlerOpts{
+				Registry: this.reg,
+			},
+		)
+	}
+	this.promHttpHandler.ServeHTTP(writer, request)
+	this.StartTests()
+}
+
+func (this *Canaries) StartTests() {
+	for _, canary := range this.canaries {
+		canary.StartTests()
+	}
+}
@@ -18,25 +18,23 @@ package canary
 
 import (
 	"context"
-	"net/http"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/SENERGY-Platform/canary/pkg/configuration"
 	"github.com/SENERGY-Platform/canary/pkg/devicemetadata"
+	"github.com/SENERGY-Platform/canary/pkg/devicesimulator"
 	"github.com/SENERGY-Platform/canary/pkg/events"
 	"github.com/SENERGY-Platform/canary/pkg/metrics"
 	"github.com/SENERGY-Platform/canary/pkg/process"
 	devicerepo "github.com/SENERGY-Platform/device-repository/lib/client"
 	"github.com/prometheus/client_golang/prometheus"
-	"github.com/prometheus/client_golang/prometheus/promhttp"
 )
 
 type Canary struct {
 	metrics              *metrics.Metrics
-	reg                  *prometheus.Registry
 	config               configuration.Config
-	promHttpHandler      http.Handler
 	isRunningMux         sync.Mutex
 	isRunning            bool
 	guaranteeChangeAfter time.Duration
@@ -44,11 +42,24 @@ type Canary struct {
 	process              Process
 	events               Event
 	devicemeta           *devicemetadata.DeviceMetaData
+	tokenMux             sync.Mutex
+	token                *OpenidToken
+	oidcMux              sync.Mutex
+	oidc                 *OpenidConfiguration
+	persistentConnMux    sync.Mutex
+	persistentConn       Conn
+	commandQosMux        sync.Mutex
+	commandQosIndex      int
+	commandScenarioMux   sync.Mutex
+	commandScenarioIndex int
+	simulator            *devicesimulator.DeviceSimulator
+	typedValueFlag       bool
+	lastCommandResponse  atomic.Pointer[string]
 }
 
-func New(ctx context.Context, wg *sync.WaitGroup, config configuration.Config) (canary *Canary, err error) {
+// New creates a Canary for a single environment. metrics are registered at reg.
+func New(ctx context.Context, wg *sync.WaitGroup, config configuration.Config, reg prometheus.Registerer) (canary *Canary, err error) {
 	guaranteeChangeAfter, err := time.ParseDuration(config.GuaranteeChangeAfter)
-	reg := prometheus.NewRegistry()
 
 	m := metrics.NewMetrics(reg)
 
@@ -59,8 +70,12 @@ func New(ctx context.Context, wg *sync.WaitGroup, config configuration.Config) (
 
 	e := events.New(config, d, m, guaranteeChangeAfter)
 
-	return &Canary{
-		reg:                  reg,
+	simulator, err := devicesimulator.New(config)
+	if err != nil {
+		return nil, err
+	}
+
+	canary = &Canary{
 		metrics:              m,
 		config:               config,
 		devicerepo:           d,
@@ -68,12 +83,26 @@ func New(ctx context.Context, wg *sync.WaitGroup, config configuration.Config) (
 		devicemeta:           devicemeta,
 		process:              p,
 		events:               e,
-	}, nil
+		simulator:            simulator,
+	}
+	canary.initBrokerMetrics()
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		<-ctx.Done()
+		canary.closePersistentConn()
+		canary.logoutCachedToken()
+	}()
+
+	return canary, nil
 }
 
 type Process interface {
 	NotifyCommand(topic string, payload []byte)
-	ProcessStartup(token string, info DeviceInfo) error
+	NotifyCommandDropped()
+	NotifyCommandResponse(value string)
+	ProcessStartup(token string, info DeviceInfo, scenario string) error
 	ProcessTeardown(token string) error
 }
 
@@ -82,24 +111,6 @@ type Event interface {
 	ProcessTeardown(token string) error
 }
 
-func (this *Canary) GetMetricsHandler() (h http.Handler, err error) {
-	return this, nil
-}
-
-func (this *Canary) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
-	this.config.GetLogger().Info("request", "method", request.Method, "url", request.URL, "remote_addr", request.RemoteAddr)
-	if this.promHttpHandler == nil {
-		this.promHttpHandler = promhttp.HandlerFor(
-			this.reg,
-			promhttp.HandlerOpts{
-				Registry: this.reg,
-			},
-		)
-	}
-	this.promHttpHandler.ServeHTTP(writer, request)
-	this.StartTests()
-}
-
 func (this *Canary) StartTests() {
 	go func() {
 		this.config.GetLogger().Info("start canary tests")
@@ -112,11 +123,10 @@ func (this *Canary) StartTests() {
 		defer this.config.GetLogger().Info("canary tests are finished")
 		wg := &sync.WaitGroup{}
 
-		token, refresh, err := this.login()
+		token, err := this.getToken()
 		if err != nil {
 			return
 		}
-		defer this.logout(token, refresh)
 
 		deviceInfo, err := this.devicemeta.EnsureDevice(token)
 		if err != nil {
@@ -129,6 +139,8 @@ func (this *Canary) StartTests() {
 
 		this.testNotification(wg, token)
 
+		this.testAuth(wg)
+
 		wg.Wait()
 
 	}()
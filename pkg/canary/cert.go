@@ -24,6 +24,8 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
+	"slices"
 	"time"
 
 	"github.com/SENERGY-Platform/cert-certificate-authority/pkg/client"
@@ -31,6 +33,9 @@ import (
 
 var ErrNewCertNeeded = errors.New("new cert needed")
 
+// ErrCertRenewalDue is returned with a still valid cert that expires within CertRenewBefore
+var ErrCertRenewalDue = fmt.Errorf("%w: cert expires soon", ErrNewCertNeeded)
+
 func (this *Canary) getTlsConfig(token string, hubId string, exp time.Duration) (*tls.Config, error) {
 	cert, err := this.getCert(token, hubId, exp)
 	if err != nil {
@@ -42,18 +47,30 @@ func (this *Canary) getTlsConfig(token string, hubId string, exp time.Duration)
 }
 
 func (this *Canary) getCert(token string, hubId string, exp time.Duration) (cert tls.Certificate, err error) {
-	cert, err = this.loadClientCertFromFile()
+	cert, err = this.loadClientCertFromFile(hubId)
 	if errors.Is(err, ErrNewCertNeeded) {
-		err = this.loadNewCertsToFiles(token, hubId, exp)
-		if err != nil {
-			return cert, err
+		renewErr := this.loadNewCertsToFiles(token, hubId, exp)
+		if renewErr != nil && errors.Is(err, ErrCertRenewalDue) {
+			this.config.GetLogger().Warn("unable to renew cert ahead of expiry; use current cert", "error", renewErr, "not_after", cert.Leaf.NotAfter)
+			return cert, nil
+		}
+		if renewErr != nil {
+			return cert, renewErr
+		}
+		cert, err = this.loadClientCertFromFile(hubId)
+		if errors.Is(err, ErrCertRenewalDue) {
+			//renewing again would not result in a longer valid cert
+			this.config.GetLogger().Warn("new cert expires within cert_renew_before; use new cert", "not_after", cert.Leaf.NotAfter)
+			return cert, nil
 		}
-		return this.loadClientCertFromFile()
+		return cert, err
 	}
-	return this.loadClientCertFromFile()
+	return cert, err
 }
 
-func (this *Canary) loadClientCertFromFile() (cert tls.Certificate, err error) {
+// loadClientCertFromFile loads the stored client cert.
+// ErrNewCertNeeded is returned if the cert is missing, expired, not issued for hubId or not issued by the ca in CertAuthorityCertFilePath.
+func (this *Canary) loadClientCertFromFile(hubId string) (cert tls.Certificate, err error) {
 	keyPath := this.config.CertKeyFilePath
 	certPath := this.config.CertFilePath
 	if _, err = os.Stat(keyPath); errors.Is(err, os.ErrNotExist) {
@@ -67,12 +84,66 @@ func (this *Canary) loadClientCertFromFile() (cert tls.Certificate, err error) {
 		this.config.GetLogger().Error("ERROR: tls.LoadX509KeyPair()", "error", err)
 		return cert, errors.Join(ErrNewCertNeeded, err)
 	}
-	if cert.Leaf != nil && !cert.Leaf.NotAfter.IsZero() && cert.Leaf.NotAfter.Before(time.Now()) {
-		return cert, fmt.Errorf("%w: cert expired", ErrNewCertNeeded)
+	err = this.verifyClientCert(cert.Leaf, hubId)
+	if err != nil {
+		this.metrics.ClientCertMismatchCount.Inc()
+		this.config.GetLogger().Warn("cached client cert does not match, request new cert", "error", err, "hub", hubId)
+		return cert, errors.Join(ErrNewCertNeeded, err)
+	}
+	if cert.Leaf != nil && !cert.Leaf.NotAfter.IsZero() {
+		this.metrics.ClientCertExpiryTimestampSeconds.Set(float64(cert.Leaf.NotAfter.Unix()))
+		if cert.Leaf.NotAfter.Before(time.Now()) {
+			return cert, fmt.Errorf("%w: cert expired", ErrNewCertNeeded)
+		}
+		if this.config.CertRenewBefore != "" {
+			renewBefore, err := time.ParseDuration(this.config.CertRenewBefore)
+			if err != nil {
+				this.config.GetLogger().Error("invalid cert_renew_before", "error", err)
+			} else if time.Until(cert.Leaf.NotAfter) < renewBefore {
+				return cert, ErrCertRenewalDue
+			}
+		}
 	}
 	return cert, nil
 }
 
+// verifyClientCert checks that leaf is issued for hubId (as SAN or CN)
+// and, if CertAuthorityCertFilePath is set, that it is signed by this ca
+func (this *Canary) verifyClientCert(leaf *x509.Certificate, hubId string) error {
+	if leaf == nil {
+		return errors.New("missing parsed client cert")
+	}
+	if !slices.Contains(leaf.DNSNames, hubId) && leaf.Subject.CommonName != hubId {
+		return fmt.Errorf("client cert issued for %v instead of hub %v", append(leaf.DNSNames, leaf.Subject.CommonName), hubId)
+	}
+	if this.config.CertAuthorityCertFilePath == "" {
+		return nil
+	}
+	//an unusable ca file is a configuration error; requesting new certs would not fix it
+	caPem, err := os.ReadFile(this.config.CertAuthorityCertFilePath)
+	if err != nil {
+		this.metrics.UncategorizedErr.Inc()
+		this.config.GetLogger().Error("unable to read ca cert, skip client cert issuer check", "error", err)
+		return nil
+	}
+	roots := x509.NewCertPool()
+	if !roots.AppendCertsFromPEM(caPem) {
+		this.metrics.UncategorizedErr.Inc()
+		this.config.GetLogger().Error("no ca cert found, skip client cert issuer check", "path", this.config.CertAuthorityCertFilePath)
+		return nil
+	}
+	//expiry is checked separately
+	_, err = leaf.Verify(x509.VerifyOptions{
+		Roots:       roots,
+		CurrentTime: leaf.NotBefore,
+		KeyUsages:   []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+	})
+	if err != nil {
+		return fmt.Errorf("client cert not issued by configured ca: %w", err)
+	}
+	return nil
+}
+
 func (this *Canary) loadNewCertsToFiles(token string, hubId string, exp time.Duration) error {
 	key, cert, _, err := client.NewClient(this.config.CertAuthorityUrl).NewCertAndKey(pkix.Name{}, []string{hubId}, exp, &token)
 	if err != nil {
@@ -86,6 +157,25 @@ func (this *Canary) loadNewCertsToFiles(token string, hubId string, exp time.Dur
 	return writeKeyAndCertPemFiles(this.config.CertKeyFilePath, this.config.CertFilePath, keyPem, certPem)
 }
 
+// throwawayCertRevocationReason is used to revoke certs that are only issued for a single check
+const throwawayCertRevocationReason = "cessationOfOperation"
+
+// newTlsConfigForHub issues a new cert for hubId without storing it
+func (this *Canary) newTlsConfigForHub(token string, hubId string, exp time.Duration) (*tls.Config, error) {
+	key, cert, _, err := client.NewClient(this.config.CertAuthorityUrl).NewCertAndKey(pkix.Name{}, []string{hubId}, exp, &token)
+	if err != nil {
+		return nil, err
+	}
+	return &tls.Config{
+		Certificates: []tls.Certificate{{Certificate: [][]byte{cert.Raw}, PrivateKey: key, Leaf: cert}},
+	}, nil
+}
+
+// revokeThrowawayCert revokes a cert issued by newTlsConfigForHub
+func (this *Canary) revokeThrowawayCert(token string, cert *x509.Certificate) (code int, err error) {
+	return client.NewClient(this.config.CertAuthorityUrl).Revoke(cert, throwawayCertRevocationReason, &token)
+}
+
 func privateKeyToPemBlock(key any) (*pem.Block, error) {
 	b, err := x509.MarshalPKCS8PrivateKey(key)
 	if err != nil {
@@ -104,22 +194,52 @@ func certToPemBlock(cert *x509.Certificate) *pem.Block {
 	}
 }
 
-func writePemFile(pth string, block *pem.Block, perm os.FileMode) error {
-	file, err := os.OpenFile(pth, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, perm)
+// writeTempPemFile writes block to a new temp file next to pth and returns the name of the temp file
+func writeTempPemFile(pth string, block *pem.Block, perm os.FileMode) (tempPath string, err error) {
+	file, err := os.CreateTemp(filepath.Dir(pth), filepath.Base(pth)+".tmp*")
 	if err != nil {
-		return err
+		return "", err
+	}
+	tempPath = file.Name()
+	err = file.Chmod(perm)
+	if err == nil {
+		err = pem.Encode(file, block)
 	}
-	defer file.Close()
-	return pem.Encode(file, block)
+	if err == nil {
+		err = file.Sync()
+	}
+	closeErr := file.Close()
+	if err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(tempPath)
+		return "", err
+	}
+	return tempPath, nil
 }
 
+// writeKeyAndCertPemFiles replaces the key and cert files by renaming completely written temp files.
+// if the process stops between both renames, tls.LoadX509KeyPair detects the mismatched pair and a new cert is requested.
 func writeKeyAndCertPemFiles(keyPath string, certPath string, keyBlock *pem.Block, certBlock *pem.Block) error {
-	err := writePemFile(keyPath, keyBlock, 0600)
+	tempKeyPath, err := writeTempPemFile(keyPath, keyBlock, 0600)
+	if err != nil {
+		return err
+	}
+	tempCertPath, err := writeTempPemFile(certPath, certBlock, 0600)
+	if err != nil {
+		_ = os.Remove(tempKeyPath)
+		return err
+	}
+	err = os.Rename(tempKeyPath, keyPath)
 	if err != nil {
+		_ = os.Remove(tempKeyPath)
+		_ = os.Remove(tempCertPath)
 		return err
 	}
-	err = writePemFile(certPath, certBlock, 0600)
+	err = os.Rename(tempCertPath, certPath)
 	if err != nil {
+		_ = os.Remove(tempCertPath)
 		return err
 	}
 	return nil
@@ -17,7 +17,17 @@
 package canary
 
 import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
 	"log"
+	"math/big"
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -45,12 +55,12 @@ func TestCertMqtt(t *testing.T) {
 	canary := Canary{config: config, metrics: m}
 	hubId := "test-hub-id"
 
-	token, refresh, err := canary.login()
+	token, err := canary.getToken()
 	if err != nil {
 		t.Error(err)
 		return
 	}
-	defer canary.logout(token, refresh)
+	defer canary.logoutCachedToken()
 
 	tlsConf, err := canary.getTlsConfig(token, hubId, time.Hour)
 	if err != nil {
@@ -82,3 +92,79 @@ func TestCertMqtt(t *testing.T) {
 	time.Sleep(time.Second * 10)
 
 }
+
+func TestCertRenewBefore(t *testing.T) {
+	dir := t.TempDir()
+	keyPath, certPath := writeTestCert(t, dir, "test-hub-id", time.Now().Add(time.Hour))
+	config := configuration.Config{
+		CertKeyFilePath: keyPath,
+		CertFilePath:    certPath,
+		CertRenewBefore: "2h",
+	}
+	canary := Canary{config: config, metrics: metrics.NewMetrics(prometheus.NewRegistry())}
+
+	files, _ := os.ReadDir(dir)
+	if len(files) != 2 {
+		t.Error("unexpected files", files)
+	}
+
+	_, err := canary.loadClientCertFromFile("test-hub-id")
+	if !errors.Is(err, ErrCertRenewalDue) || !errors.Is(err, ErrNewCertNeeded) {
+		t.Error("expected renewal", err)
+	}
+
+	canary.config.CertRenewBefore = "30m"
+	_, err = canary.loadClientCertFromFile("test-hub-id")
+	if err != nil {
+		t.Error(err)
+	}
+
+	_, err = canary.loadClientCertFromFile("other-hub-id")
+	if !errors.Is(err, ErrNewCertNeeded) {
+		t.Error("expected new cert for other hub", err)
+	}
+
+	//the self-signed cert is its own ca
+	canary.config.CertAuthorityCertFilePath = config.CertFilePath
+	_, err = canary.loadClientCertFromFile("test-hub-id")
+	if err != nil {
+		t.Error(err)
+	}
+
+	_, otherCaPath := writeTestCert(t, t.TempDir(), "other-ca", time.Now().Add(time.Hour))
+	canary.config.CertAuthorityCertFilePath = otherCaPath
+	_, err = canary.loadClientCertFromFile("test-hub-id")
+	if !errors.Is(err, ErrNewCertNeeded) {
+		t.Error("expected new cert for other ca", err)
+	}
+}
+
+// writeTestCert stores a new self-signed cert for commonName and its key in dir
+func writeTestCert(t *testing.T, dir string, commonName string, notAfter time.Time) (keyPath string, certPath string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     notAfter,
+	}
+	raw, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyPem, err := privateKeyToPemBlock(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyPath = filepath.Join(dir, "key.pem")
+	certPath = filepath.Join(dir, "cert.pem")
+	err = writeKeyAndCertPemFiles(keyPath, certPath, keyPem, &pem.Block{Type: "CERTIFICATE", Bytes: raw})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return keyPath, certPath
+}
@@ -0,0 +1,115 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package canary
+
+import (
+	"time"
+
+	"github.com/SENERGY-Platform/canary/pkg/configuration"
+	"github.com/google/uuid"
+)
+
+const CertAuthorityStepIssue = "issue"
+const CertAuthorityStepConnect = "connect"
+const CertAuthorityStepRevoke = "revoke"
+const CertAuthorityStepRefuse = "refuse"
+
+// testCertAuthority runs the lifecycle of a short-lived cert for a throwaway hub with the first cert broker:
+// the cert is issued, used to connect, revoked and must then be refused by the connector.
+// the throwaway hub exists only during the check, because the connector only accepts known hubs.
+func (this *Canary) testCertAuthority(token string, brokers []configuration.ConnectorBroker) {
+	broker, ok := firstCertBroker(brokers)
+	if !ok {
+		this.config.GetLogger().Info("skip certificate authority check: no broker uses certs")
+		return
+	}
+	exp, err := time.ParseDuration(this.config.CertAuthorityCheckExpTime)
+	if err != nil {
+		this.config.GetLogger().Error("invalid cert_authority_check_exp_time", "error", err)
+		this.metrics.UncategorizedErr.Inc()
+		return
+	}
+
+	//the name must not match the search for the canary hub
+	hubId, err := this.createHub(token, HubInfo{Name: "ca-check-" + uuid.NewString()})
+	if err != nil {
+		return
+	}
+	defer this.deleteHub(token, hubId)
+
+	this.metrics.CertAuthorityStepCount.WithLabelValues(CertAuthorityStepIssue).Inc()
+	start := time.Now()
+	tlsConf, err := this.newTlsConfigForHub(token, hubId, exp)
+	this.metrics.CertAuthorityStepLatencyMs.WithLabelValues(CertAuthorityStepIssue).Set(float64(time.Since(start).Milliseconds()))
+	if err != nil {
+		this.config.GetLogger().Error("unable to issue cert for throwaway hub", "error", err, "hub", hubId)
+		this.metrics.CertAuthorityStepErr.WithLabelValues(CertAuthorityStepIssue).Inc()
+		return
+	}
+	cert := tlsConf.Certificates[0].Leaf
+
+	this.metrics.CertAuthorityStepCount.WithLabelValues(CertAuthorityStepConnect).Inc()
+	start = time.Now()
+	conn, reasonCode, err := this.openConn(hubId, broker, tlsConf)
+	this.metrics.CertAuthorityStepLatencyMs.WithLabelValues(CertAuthorityStepConnect).Set(float64(time.Since(start).Milliseconds()))
+	connected := err == nil
+	if err != nil {
+		//the cert is revoked anyway to not leave a valid cert behind
+		this.config.GetLogger().Error("unable to connect with cert of throwaway hub", "error", err, "reason_code", formatReasonCode(reasonCode), "broker", broker.Name)
+		this.metrics.CertAuthorityStepErr.WithLabelValues(CertAuthorityStepConnect).Inc()
+	} else {
+		conn.Disconnect()
+	}
+
+	this.metrics.CertAuthorityStepCount.WithLabelValues(CertAuthorityStepRevoke).Inc()
+	start = time.Now()
+	code, err := this.revokeThrowawayCert(token, cert)
+	this.metrics.CertAuthorityStepLatencyMs.WithLabelValues(CertAuthorityStepRevoke).Set(float64(time.Since(start).Milliseconds()))
+	if err != nil {
+		this.config.GetLogger().Error("unable to revoke cert of throwaway hub", "error", err, "status-code", code, "serial", cert.SerialNumber.String())
+		this.metrics.CertAuthorityStepErr.WithLabelValues(CertAuthorityStepRevoke).Inc()
+		return
+	}
+
+	//a refused connection is only meaningful if the cert has been accepted before the revocation
+	if !connected {
+		return
+	}
+
+	time.Sleep(this.getChangeGuaranteeDuration())
+
+	this.metrics.CertAuthorityStepCount.WithLabelValues(CertAuthorityStepRefuse).Inc()
+	start = time.Now()
+	conn, reasonCode, err = this.openConn(hubId, broker, tlsConf)
+	this.metrics.CertAuthorityStepLatencyMs.WithLabelValues(CertAuthorityStepRefuse).Set(float64(time.Since(start).Milliseconds()))
+	if err != nil {
+		this.config.GetLogger().Debug("connection with revoked cert refused", "error", err, "reason_code", formatReasonCode(reasonCode), "broker", broker.Name)
+		return
+	}
+	conn.Disconnect()
+	this.config.GetLogger().Error("SECURITY: connection with revoked cert accepted", "reason_code", formatReasonCode(reasonCode), "broker", broker.Name, "serial", cert.SerialNumber.String())
+	this.metrics.CertAuthorityStepErr.WithLabelValues(CertAuthorityStepRefuse).Inc()
+}
+
+func firstCertBroker(brokers []configuration.ConnectorBroker) (broker configuration.ConnectorBroker, ok bool) {
+	for _, broker = range brokers {
+		if broker.UseCert {
+			return broker, true
+		}
+	}
+	return broker, false
+}
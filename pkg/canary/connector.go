@@ -18,9 +18,13 @@ package canary
 
 import (
 	"bytes"
+	"crypto/tls"
 	"encoding/json"
-	"math/rand"
+	"errors"
+	"fmt"
+	"net"
 	"net/http"
+	"net/url"
 	"reflect"
 	"runtime/debug"
 	"strconv"
@@ -28,10 +32,11 @@ import (
 	"sync"
 	"time"
 
+	"github.com/SENERGY-Platform/canary/pkg/configuration"
 	"github.com/SENERGY-Platform/canary/pkg/devicemetadata"
 	"github.com/SENERGY-Platform/device-repository/lib/model"
 	"github.com/SENERGY-Platform/models/go/models"
-	paho "github.com/eclipse/paho.mqtt.golang"
+	"github.com/prometheus/client_golang/prometheus"
 )
 
 func (this *Canary) testDeviceConnection(wg *sync.WaitGroup, token string, info DeviceInfo) {
@@ -39,134 +44,375 @@ func (this *Canary) testDeviceConnection(wg *sync.WaitGroup, token string, info
 	go func() {
 		defer wg.Done()
 
-		eventDeplErr := this.events.ProcessStartup(token, info)
-
-		this.checkDeviceConnState(token, info, false)
-
 		hubId, err := this.ensureHub(token, info)
 		if err != nil {
 			return
 		}
 
-		conn, err := this.connect(token, hubId)
-		if err != nil {
-			return
+		//brokers are checked one after another, because they share the hub id as client id
+		brokers := this.config.GetConnectorBrokers()
+		if this.config.ConnectorPersistentConnection {
+			this.testPersistentBrokerConnections(token, info, hubId, brokers)
+		} else {
+			for i, broker := range brokers {
+				if i > 0 {
+					time.Sleep(this.getChangeGuaranteeDuration())
+				}
+				//process checks depend on a single connected device; they run with the first broker
+				this.testBrokerConnection(this.phaseToken(token), info, hubId, broker, i == 0, false)
+			}
 		}
 
-		this.subscribe(info, conn)
+		if this.config.ConnectorAclCheck {
+			this.testConnectorAcl(this.phaseToken(token), info, hubId, brokers)
+		}
 
-		value := rand.Int()
+		if this.config.CertAuthorityCheck {
+			this.testCertAuthority(this.phaseToken(token), brokers)
+		}
+	}()
+}
 
-		this.publish(info, conn, value)
+// testBrokerConnection checks the device connection with one broker.
+// persistent connections are reused and stay connected after the check.
+func (this *Canary) testBrokerConnection(token string, info DeviceInfo, hubId string, broker configuration.ConnectorBroker, withProcesses bool, persistent bool) {
+	eventDeplErr := errSkipped
+	if withProcesses {
+		eventDeplErr = this.events.ProcessStartup(token, info)
+	}
 
-		processErr := this.process.ProcessStartup(token, info)
+	var conn Conn
+	var err error
+	if persistent {
+		conn, err = this.getPersistentConn(token, hubId, broker)
+	} else {
+		this.checkDeviceConnState(token, info, broker, false)
+		conn, err = this.connect(token, hubId, broker)
+	}
+	if err != nil {
+		return
+	}
 
-		time.Sleep(this.getChangeGuaranteeDuration())
+	//only the process check sends commands; without it the device answers every command with a response
+	scenario := configuration.CommandScenarioSuccess
+	if withProcesses {
+		scenario = this.nextCommandScenario()
+	}
+
+	this.subscribe(info, conn, this.nextCommandQos(), scenario)
+
+	qosLevels := this.config.GetConnectorQosLevels()
 
-		this.checkDeviceConnState(token, info, true)
+	value := this.simulator.NextEventValue()
 
-		this.checkDeviceValue(token, info, value)
+	//published values are checked with the historic data query
+	published := []int{}
+	runStart := time.Now()
+	if this.publish(info, conn, value, qosLevels[0]) == nil {
+		published = append(published, value)
+	}
+
+	processErr := errSkipped
+	if withProcesses {
+		processErr = this.process.ProcessStartup(token, info, scenario)
+	}
+
+	time.Sleep(this.getChangeGuaranteeDuration())
+
+	token = this.phaseToken(token)
+	this.checkDeviceConnState(token, info, broker, true)
 
-		if processErr == nil {
-			this.process.ProcessTeardown(token)
+	this.checkDeviceValue(token, info, broker, value, qosLevels[0])
+
+	//the last value query only returns the latest value; every qos level is checked with its own value
+	for _, qos := range qosLevels[1:] {
+		value = this.simulator.NextEventValue()
+		if this.publish(info, conn, value, qos) == nil {
+			published = append(published, value)
 		}
+		time.Sleep(this.getChangeGuaranteeDuration())
+		token = this.phaseToken(token)
+		this.checkDeviceValue(token, info, broker, value, qos)
+	}
+
+	//typed values are checked once per run with the first broker
+	if withProcesses && this.config.ConnectorTypedValueCheck {
+		this.testTypedValues(this.phaseToken(token), info, conn)
+	}
 
-		this.disconnect(conn)
+	//characteristic conversions are checked once per run with the first broker
+	if withProcesses && this.config.ConnectorConversionCheck {
+		this.testCharacteristicConversion(this.phaseToken(token), info, conn)
+	}
+
+	//device timestamps are checked once per run with the first broker
+	if withProcesses && this.config.ConnectorTimestampCheck {
+		this.testDeviceTimestamps(this.phaseToken(token), info, conn)
+	}
+
+	//historic data is checked once per run with the first broker
+	if withProcesses && this.config.ConnectorHistoricCheck {
+		this.testHistoricQuery(this.phaseToken(token), info, published, runStart)
+	}
+
+	//message loss and order are checked once per run with the first broker
+	if withProcesses && this.config.ConnectorBurstCheck {
+		this.testBurst(this.phaseToken(token), info, conn)
+	}
+
+	//device error notifications are checked once per run with the first broker
+	if withProcesses && this.config.ConnectorDeviceErrorCheck {
+		this.testDeviceErrorNotification(this.phaseToken(token), info, conn)
+	}
 
-		if eventDeplErr == nil {
-			time.Sleep(this.getChangeGuaranteeDuration())
-			this.events.ProcessTeardown(token)
+	if processErr == nil {
+		this.process.ProcessTeardown(this.phaseToken(token))
+	}
+
+	//the device-command api is checked once per run with the first broker, after the process check to not interfere with its command.
+	//every qos level gets its own command subscription and command, to check the command-response flow per qos level in every run.
+	if withProcesses && this.config.DeviceCommandCheck {
+		for _, qos := range qosLevels {
+			this.subscribe(info, conn, qos, configuration.CommandScenarioSuccess)
+			this.testDeviceCommand(this.phaseToken(token), info)
 		}
+	}
 
-	}()
+	if !persistent {
+		token = this.phaseToken(token)
+		this.checkOfflineDetection(token, info, conn, false)
+		this.testUngracefulDisconnect(token, info, hubId, broker)
+	}
+
+	if eventDeplErr == nil {
+		time.Sleep(this.getChangeGuaranteeDuration())
+		this.events.ProcessTeardown(this.phaseToken(token))
+	}
 }
 
+var errSkipped = errors.New("skipped")
+
 type PermDevice = devicemetadata.PermDevice
 
-func (this *Canary) checkDeviceConnState(token string, info DeviceInfo, expectedConnState bool) {
-	this.metrics.DeviceRepoRequestCount.Inc()
-	start := time.Now()
-	device, err, _ := this.devicerepo.ReadExtendedDevice(info.Id, token, model.READ, false)
-	this.metrics.DeviceRepoRequestLatencyMs.Set(float64(time.Since(start).Milliseconds()))
+func (this *Canary) checkDeviceConnState(token string, info DeviceInfo, broker configuration.ConnectorBroker, expectedConnState bool) {
+	online, err := this.isDeviceOnline(token, info)
 	if err != nil {
-		this.config.GetLogger().Error("unable to read device", "error", err)
-		this.metrics.DeviceRepoRequestErr.Inc()
 		return
 	}
-	if (device.ConnectionState == models.ConnectionStateOnline) != expectedConnState {
-		this.config.GetLogger().Warn("Unexpected device connection-state", "actual", device.ConnectionState, "expected", expectedConnState)
+	if online != expectedConnState {
+		this.config.GetLogger().Warn("Unexpected device connection-state", "actual", online, "expected", expectedConnState, "broker", broker.Name)
 		if expectedConnState {
-			this.metrics.UnexpectedDeviceOfflineStateErr.Inc()
+			this.metrics.UnexpectedDeviceOfflineStateErr.WithLabelValues(brokerLabels(broker)...).Inc()
 		} else {
-			this.metrics.UnexpectedDeviceOnlineStateErr.Inc()
+			this.metrics.UnexpectedDeviceOnlineStateErr.WithLabelValues(brokerLabels(broker)...).Inc()
 		}
 	}
 }
 
-type Conn struct {
-	Client paho.Client
+func (this *Canary) isDeviceOnline(token string, info DeviceInfo) (online bool, err error) {
+	this.metrics.DeviceRepoRequestCount.Inc()
+	start := time.Now()
+	device, err, _ := this.devicerepo.ReadExtendedDevice(info.Id, token, model.READ, false)
+	this.metrics.DeviceRepoRequestLatencyMs.Set(float64(time.Since(start).Milliseconds()))
+	if err != nil {
+		this.config.GetLogger().Error("unable to read device", "error", err)
+		this.metrics.DeviceRepoRequestErr.Inc()
+		return false, err
+	}
+	return device.ConnectionState == models.ConnectionStateOnline, nil
 }
 
-func (this *Canary) connect(token string, hubId string) (conn *Conn, err error) {
-	conn = &Conn{}
+// Conn is the connection of the canary hub to one connector broker.
+// reason codes of acknowledgement packets are returned as int; NoReasonCode is used if none is known
+type Conn interface {
+	GetBroker() configuration.ConnectorBroker
+	IsConnected() bool
+	Subscribe(topic string, qos byte, handler func(topic string, payload []byte)) (reasonCode int, err error)
+	Publish(topic string, qos byte, payload []byte) (reasonCode int, err error)
+	Disconnect()
+	// Kill closes the network connection without sending a disconnect packet
+	Kill() error
+	// ExpectLoss stops reporting lost connections as errors (e.g. if the connector is expected to drop the connection)
+	ExpectLoss()
+	// LostCount returns how often the connection was lost
+	LostCount() int
+}
 
-	options := paho.NewClientOptions().
-		SetClientID(hubId).
-		SetAutoReconnect(true).
-		SetCleanSession(true).
-		AddBroker(this.config.ConnectorMqttBrokerUrl).
-		SetConnectionLostHandler(func(c paho.Client, err error) {
-			this.config.GetLogger().Error("lost connection", "error", err)
-		})
+// NoReasonCode is used if no reason code was received (e.g. pubacks of mqtt 3.1.1 or connection errors)
+const NoReasonCode = -1
 
-	if this.config.UseCert {
+func (this *Canary) connect(token string, hubId string, broker configuration.ConnectorBroker) (conn Conn, err error) {
+	var tlsConf *tls.Config
+	if broker.UseCert {
 		exp, err := time.ParseDuration(this.config.CertExpTime)
 		if err != nil {
 			return conn, err
 		}
-		tlsConf, err := this.getTlsConfig(token, hubId, exp)
+		tlsConf, err = this.getTlsConfig(token, hubId, exp)
 		if err != nil {
 			return conn, err
 		}
-		options = options.SetTLSConfig(tlsConf)
-	} else {
-		options = options.SetUsername(this.config.AuthUsername).SetPassword(this.config.AuthPassword)
 	}
 
-	this.metrics.ConnectorLoginCount.Inc()
-	conn.Client = paho.NewClient(options)
+	this.metrics.ConnectorLoginCount.WithLabelValues(brokerLabels(broker)...).Inc()
 	start := time.Now()
-	conToken := conn.Client.Connect()
-	conToken.Wait()
-	this.metrics.ConnectorLoginLatencyMs.Set(float64(time.Since(start).Milliseconds()))
-	if conToken.Error() != nil {
-		this.config.GetLogger().Error("unable to connect", "error", conToken.Error())
-		this.metrics.ConnectorLoginErr.Inc()
-		return conn, conToken.Error()
+	conn, reasonCode, err := this.openConn(hubId, broker, tlsConf)
+	this.metrics.ConnectorLoginLatencyMs.WithLabelValues(brokerLabels(broker)...).Set(float64(time.Since(start).Milliseconds()))
+	this.countReasonCode(broker, "connack", reasonCode)
+	if err != nil {
+		this.config.GetLogger().Error("unable to connect", "error", err, "reason_code", formatReasonCode(reasonCode), "broker", broker.Name)
+		this.metrics.ConnectorLoginErr.WithLabelValues(brokerLabels(broker)...).Inc()
+		return nil, err
 	}
 	return conn, nil
 }
 
-func (this *Canary) disconnect(conn *Conn) {
-	conn.Client.Disconnect(250)
+// openConn connects with the protocol version of broker
+func (this *Canary) openConn(hubId string, broker configuration.ConnectorBroker, tlsConf *tls.Config) (conn Conn, reasonCode int, err error) {
+	if broker.GetProtocolVersion() == configuration.MqttProtocolVersion5 {
+		mqtt5Conn, reasonCode, err := this.connectMqtt5(hubId, broker, tlsConf)
+		if err != nil {
+			return nil, reasonCode, err
+		}
+		return mqtt5Conn, reasonCode, nil
+	}
+	mqtt3Conn, reasonCode, err := this.connectMqtt3(hubId, broker, tlsConf)
+	if err != nil {
+		return nil, reasonCode, err
+	}
+	return mqtt3Conn, reasonCode, nil
+}
+
+// brokerLabels returns the values for metrics.ConnectorLabels
+func brokerLabels(broker configuration.ConnectorBroker) []string {
+	return []string{broker.Name, broker.GetTransport(), strconv.Itoa(broker.GetProtocolVersion())}
+}
+
+// initBrokerMetrics creates the counters of every configured broker and qos level,
+// to export them with 0 before the first check or error
+func (this *Canary) initBrokerMetrics() {
+	for _, broker := range this.config.GetConnectorBrokers() {
+		labels := brokerLabels(broker)
+		for _, vec := range []*prometheus.CounterVec{
+			this.metrics.ConnectorLoginCount,
+			this.metrics.ConnectorLoginErr,
+			this.metrics.ConnectorSubscribeCount,
+			this.metrics.ConnectorSubscribeErr,
+			this.metrics.ConnectorConnectionLostCount,
+			this.metrics.ConnectorReconnectCount,
+			this.metrics.UnexpectedDeviceOnlineStateErr,
+			this.metrics.UnexpectedDeviceOfflineStateErr,
+		} {
+			vec.WithLabelValues(labels...)
+		}
+		for _, qos := range this.config.GetConnectorQosLevels() {
+			for _, vec := range []*prometheus.CounterVec{
+				this.metrics.DeviceDataRequestCount,
+				this.metrics.DeviceDataRequestErr,
+				this.metrics.ConnectorPublishCount,
+				this.metrics.ConnectorPublishErr,
+				this.metrics.ConnectorCommandResponseCount,
+				this.metrics.ConnectorCommandResponseErr,
+				this.metrics.UnexpectedDeviceDataErr,
+			} {
+				vec.WithLabelValues(qosLabels(broker, qos)...)
+			}
+		}
+		for _, disconnect := range []string{DisconnectClean, DisconnectUngraceful} {
+			this.metrics.ConnectorOfflineDetectionSlaErr.WithLabelValues(append(brokerLabels(broker), disconnect)...)
+		}
+		for _, attempt := range []string{AclAttemptWrongCredentials, AclAttemptForeignCert, AclAttemptForeignSubscribe, AclAttemptForeignPublish} {
+			this.metrics.ConnectorAclCheckCount.WithLabelValues(aclLabels(broker, attempt)...)
+			this.metrics.ConnectorAclViolationErr.WithLabelValues(aclLabels(broker, attempt)...)
+		}
+	}
+}
+
+func (this *Canary) countReasonCode(broker configuration.ConnectorBroker, packet string, reasonCode int) {
+	if reasonCode == NoReasonCode {
+		return
+	}
+	labels := append(brokerLabels(broker), packet, formatReasonCode(reasonCode))
+	this.metrics.ConnectorReasonCode.WithLabelValues(labels...).Inc()
+}
+
+func formatReasonCode(reasonCode int) string {
+	if reasonCode == NoReasonCode {
+		return "none"
+	}
+	return fmt.Sprintf("0x%02x", reasonCode)
+}
+
+// dialMqtt opens the network connection for mqtt over tcp or tls
+func dialMqtt(brokerUrl string, tlsConf *tls.Config) (net.Conn, error) {
+	u, err := url.Parse(brokerUrl)
+	if err != nil {
+		return nil, fmt.Errorf("invalid broker url: %w", err)
+	}
+	dialer := &net.Dialer{Timeout: mqttTimeout}
+	switch u.Scheme {
+	case "tcp", "mqtt":
+		return dialer.Dial("tcp", u.Host)
+	case "tls", "ssl", "mqtts", "tcps":
+		return tls.DialWithDialer(dialer, "tcp", u.Host, tlsConf)
+	default:
+		return nil, errors.New("unsupported broker url scheme: " + u.Scheme)
+	}
+}
+
+// nextCommandQos returns the qos level of the command subscription of the process check.
+// a process check only sends one command; the configured qos levels are used in turns.
+// the device-command check covers every qos level in each run.
+func (this *Canary) nextCommandQos() byte {
+	qosLevels := this.config.GetConnectorQosLevels()
+	this.commandQosMux.Lock()
+	defer this.commandQosMux.Unlock()
+	qos := qosLevels[this.commandQosIndex%len(qosLevels)]
+	this.commandQosIndex++
+	return qos
+}
+
+// nextCommandScenario returns how the device answers the command of the process check.
+// the configured scenarios are used in turns.
+func (this *Canary) nextCommandScenario() string {
+	scenarios := this.config.GetProcessCommandScenarios()
+	this.commandScenarioMux.Lock()
+	defer this.commandScenarioMux.Unlock()
+	scenario := scenarios[this.commandScenarioIndex%len(scenarios)]
+	this.commandScenarioIndex++
+	return scenario
 }
 
-func (this *Canary) subscribe(info DeviceInfo, conn *Conn) {
-	this.metrics.ConnectorSubscribeCount.Inc()
+// qosLabels returns the values for metrics.ConnectorQosLabels
+func qosLabels(broker configuration.ConnectorBroker, qos byte) []string {
+	return append(brokerLabels(broker), strconv.Itoa(int(qos)))
+}
+
+func (this *Canary) subscribe(info DeviceInfo, conn Conn, qos byte, scenario string) {
+	broker := conn.GetBroker()
+	this.metrics.ConnectorSubscribeCount.WithLabelValues(brokerLabels(broker)...).Inc()
 	topic := "command/" + info.LocalId + "/+"
 	if this.config.TopicsWithOwner {
 		topic = "command/" + info.OwnerId + "/" + info.LocalId + "/+"
 	}
 	start := time.Now()
-	token := conn.Client.Subscribe(topic, 2, func(c paho.Client, message paho.Message) {
-		this.process.NotifyCommand(message.Topic(), message.Payload())
-		go this.respond(conn, message.Topic(), message.Payload())
+	reasonCode, err := conn.Subscribe(topic, qos, func(topic string, payload []byte) {
+		received := time.Now()
+		this.process.NotifyCommand(topic, payload)
+		switch scenario {
+		case configuration.CommandScenarioNoResponse:
+			this.config.GetLogger().Debug("withhold command response", "topic", topic)
+		case configuration.CommandScenarioError:
+			go this.respondError(info, conn, payload, qos)
+		default:
+			go this.respond(conn, topic, payload, qos, received)
+		}
 	})
-	token.Wait()
-	this.metrics.ConnectorSubscribeLatencyMs.Set(float64(time.Since(start).Milliseconds()))
-	if token.Error() != nil {
-		this.config.GetLogger().Error("unable to subscribe", "error", token.Error())
-		this.metrics.ConnectorSubscribeErr.Inc()
+	this.metrics.ConnectorSubscribeLatencyMs.WithLabelValues(brokerLabels(broker)...).Set(float64(time.Since(start).Milliseconds()))
+	this.countReasonCode(broker, "suback", reasonCode)
+	if err != nil {
+		this.config.GetLogger().Error("unable to subscribe", "error", err, "reason_code", formatReasonCode(reasonCode), "broker", broker.Name)
+		this.metrics.ConnectorSubscribeErr.WithLabelValues(brokerLabels(broker)...).Inc()
 		return
 	}
 }
@@ -187,20 +433,34 @@ type ResponseEnvelope struct {
 	Payload       CommandResponseMsg `json:"payload"`
 }
 
-func (this *Canary) respond(conn *Conn, cmdtopic string, cmdpayload []byte) {
+func (this *Canary) respond(conn Conn, cmdtopic string, cmdpayload []byte, qos byte, received time.Time) {
+	broker := conn.GetBroker()
+	this.metrics.ConnectorCommandResponseCount.WithLabelValues(qosLabels(broker, qos)...).Inc()
 	request := RequestEnvelope{}
 	err := json.Unmarshal(cmdpayload, &request)
 	if err != nil {
 		this.config.GetLogger().Error("unable to decode request envelope", "error", err)
+		this.metrics.ConnectorCommandResponseErr.WithLabelValues(qosLabels(broker, qos)...).Inc()
+		return
+	}
+
+	simulated := this.simulator.NextResponse()
+	if simulated.Dropped {
+		this.metrics.DeviceSimulatorDroppedResponseCount.Inc()
+		this.process.NotifyCommandDropped()
+		this.config.GetLogger().Info("device simulator drops command response", "correlation_id", request.CorrelationId, "broker", broker.Name)
 		return
 	}
+	time.Sleep(simulated.Delay)
 
-	emptyResp := CommandResponseMsg{}
+	resp := CommandResponseMsg{}
 	for k, _ := range request.Payload {
-		emptyResp[k] = ""
+		resp[k] = ""
 	}
+	value := strconv.Itoa(simulated.Value)
+	resp[this.config.CanaryProtocolSegmentName] = value
 
-	payload, err := json.Marshal(ResponseEnvelope{CorrelationId: request.CorrelationId, Payload: emptyResp})
+	payload, err := json.Marshal(ResponseEnvelope{CorrelationId: request.CorrelationId, Payload: resp})
 	if err != nil {
 		this.config.GetLogger().Error("unable to encode response envelope", "error", err)
 		this.metrics.UncategorizedErr.Inc()
@@ -209,37 +469,73 @@ func (this *Canary) respond(conn *Conn, cmdtopic string, cmdpayload []byte) {
 
 	topic := strings.Replace(cmdtopic, "command/", "response/", 1)
 
-	token := conn.Client.Publish(topic, 2, false, payload)
-	token.Wait()
-	if token.Error() != nil {
-		this.config.GetLogger().Error("unable to publish response", "error", token.Error())
-		this.metrics.UncategorizedErr.Inc()
+	//the response may be processed before the publish returns (e.g. before the pubcomp of qos 2); a failed publish is counted below
+	this.process.NotifyCommandResponse(value)
+	this.lastCommandResponse.Store(&value)
+
+	reasonCode, err := conn.Publish(topic, qos, payload)
+	//the artificial delay of the device simulator is not part of the latency
+	this.metrics.ConnectorCommandResponseLatencyMs.WithLabelValues(qosLabels(broker, qos)...).Set(float64((time.Since(received) - simulated.Delay).Milliseconds()))
+	this.countReasonCode(broker, "puback", reasonCode)
+	if err != nil {
+		this.config.GetLogger().Error("unable to publish response", "error", err, "reason_code", formatReasonCode(reasonCode), "broker", broker.Name)
+		this.metrics.ConnectorCommandResponseErr.WithLabelValues(qosLabels(broker, qos)...).Inc()
 		return
 	}
 }
 
-func (this *Canary) publish(info DeviceInfo, conn *Conn, value int) {
-	payload, err := json.Marshal(map[string]string{this.config.CanaryProtocolSegmentName: strconv.Itoa(value)})
+// respondError answers a command with a command error instead of a response
+func (this *Canary) respondError(info DeviceInfo, conn Conn, cmdpayload []byte, qos byte) {
+	broker := conn.GetBroker()
+	request := RequestEnvelope{}
+	err := json.Unmarshal(cmdpayload, &request)
+	if err != nil {
+		this.config.GetLogger().Error("unable to decode request envelope", "error", err)
+		this.metrics.UncategorizedErr.Inc()
+		return
+	}
+	topic := "error/command/" + request.CorrelationId
+	if this.config.TopicsWithOwner {
+		topic = "error/command/" + info.OwnerId + "/" + request.CorrelationId
+	}
+	reasonCode, err := conn.Publish(topic, qos, []byte("canary command error"))
+	this.countReasonCode(broker, "puback", reasonCode)
 	if err != nil {
+		this.config.GetLogger().Error("unable to publish command error", "error", err, "reason_code", formatReasonCode(reasonCode), "broker", broker.Name)
 		this.metrics.UncategorizedErr.Inc()
 		return
 	}
+}
+
+func (this *Canary) publish(info DeviceInfo, conn Conn, value int, qos byte) error {
+	return this.publishEvent(info, conn, devicemetadata.SensorServiceLocalId, strconv.Itoa(value), qos)
+}
+
+// publishEvent publishes segmentValue as protocol segment content of the event service with the local id serviceLocalId
+func (this *Canary) publishEvent(info DeviceInfo, conn Conn, serviceLocalId string, segmentValue string, qos byte) error {
+	payload, err := json.Marshal(map[string]string{this.config.CanaryProtocolSegmentName: segmentValue})
+	if err != nil {
+		this.metrics.UncategorizedErr.Inc()
+		return err
+	}
 
-	this.metrics.ConnectorPublishCount.Inc()
-	topic := "event/" + info.LocalId + "/sensor"
+	broker := conn.GetBroker()
+	this.metrics.ConnectorPublishCount.WithLabelValues(qosLabels(broker, qos)...).Inc()
+	topic := "event/" + info.LocalId + "/" + serviceLocalId
 	if this.config.TopicsWithOwner {
-		topic = "event/" + info.OwnerId + "/" + info.LocalId + "/sensor"
+		topic = "event/" + info.OwnerId + "/" + info.LocalId + "/" + serviceLocalId
 	}
 
 	start := time.Now()
-	token := conn.Client.Publish(topic, 2, false, payload)
-	token.Wait()
-	this.metrics.ConnectorPublishLatencyMs.Set(float64(time.Since(start).Milliseconds()))
-	if token.Error() != nil {
-		this.config.GetLogger().Error("unable to publish", "error", token.Error())
-		this.metrics.ConnectorPublishErr.Inc()
-		return
+	reasonCode, err := conn.Publish(topic, qos, payload)
+	this.metrics.ConnectorPublishLatencyMs.WithLabelValues(qosLabels(broker, qos)...).Set(float64(time.Since(start).Milliseconds()))
+	this.countReasonCode(broker, "puback", reasonCode)
+	if err != nil {
+		this.config.GetLogger().Error("unable to publish", "error", err, "reason_code", formatReasonCode(reasonCode), "qos", qos, "broker", broker.Name)
+		this.metrics.ConnectorPublishErr.WithLabelValues(qosLabels(broker, qos)...).Inc()
+		return err
 	}
+	return nil
 }
 
 type LastValue struct {
@@ -247,7 +543,7 @@ type LastValue struct {
 	Value interface{} `json:"value"`
 }
 
-func (this *Canary) checkDeviceValue(token string, info DeviceInfo, value int) {
+func (this *Canary) checkDeviceValue(token string, info DeviceInfo, broker configuration.ConnectorBroker, value int, qos byte) {
 	this.metrics.DeviceRepoRequestCount.Inc()
 	start := time.Now()
 	dt, err, _ := this.devicerepo.ReadDeviceType(info.DeviceTypeId, token)
@@ -267,30 +563,17 @@ func (this *Canary) checkDeviceValue(token string, info DeviceInfo, value int) {
 		}
 	}
 
-	buf := &bytes.Buffer{}
-	body := []map[string]interface{}{{
-		"deviceId":   info.Id,
-		"serviceId":  serviceId,
-		"columnName": "value",
+	body := []LastValueRequest{{
+		DeviceId:   info.Id,
+		ServiceId:  serviceId,
+		ColumnName: "value",
 	}}
-	err = json.NewEncoder(buf).Encode(body)
-	if err != nil {
-		return
-	}
-	this.metrics.DeviceDataRequestCount.Inc()
-	req, err := http.NewRequest(http.MethodPost, this.config.LastValueQueryUrl, buf)
-	if err != nil {
-		this.metrics.UncategorizedErr.Inc()
-		this.config.GetLogger().Error("unable to create last value query http request", "error", err)
-		debug.PrintStack()
-		return
-	}
-	req.Header.Set("Authorization", token)
+	this.metrics.DeviceDataRequestCount.WithLabelValues(qosLabels(broker, qos)...).Inc()
 	start = time.Now()
-	lastValues, _, err := devicemetadata.Do[[]LastValue](req)
-	this.metrics.DeviceDataRequestLatencyMs.Set(float64(time.Since(start).Milliseconds()))
+	lastValues, err := this.queryLastValues(token, body)
+	this.metrics.DeviceDataRequestLatencyMs.WithLabelValues(qosLabels(broker, qos)...).Set(float64(time.Since(start).Milliseconds()))
 	if err != nil {
-		this.metrics.DeviceDataRequestErr.Inc()
+		this.metrics.DeviceDataRequestErr.WithLabelValues(qosLabels(broker, qos)...).Inc()
 		this.config.GetLogger().Error("unable to read last value", "error", err, "body", body, "dt", dt)
 		debug.PrintStack()
 	}
@@ -298,18 +581,44 @@ func (this *Canary) checkDeviceValue(token string, info DeviceInfo, value int) {
 	expected := jsonNormalize(value)
 
 	if len(lastValues) != 1 {
-		this.metrics.UnexpectedDeviceDataErr.Inc()
-		this.config.GetLogger().Error("unexpected last value list count", "count", len(lastValues))
+		this.metrics.UnexpectedDeviceDataErr.WithLabelValues(qosLabels(broker, qos)...).Inc()
+		this.config.GetLogger().Error("unexpected last value list count", "count", len(lastValues), "qos", qos, "broker", broker.Name)
 		return
 	}
 
 	if !reflect.DeepEqual(lastValues[0].Value, expected) {
-		this.metrics.UnexpectedDeviceDataErr.Inc()
-		this.config.GetLogger().Error("unexpected last value", "expected", expected, "actual", lastValues[0].Value)
+		this.metrics.UnexpectedDeviceDataErr.WithLabelValues(qosLabels(broker, qos)...).Inc()
+		this.config.GetLogger().Error("unexpected last value", "expected", expected, "actual", lastValues[0].Value, "qos", qos, "broker", broker.Name)
 		return
 	}
 }
 
+type LastValueRequest struct {
+	DeviceId               string `json:"deviceId"`
+	ServiceId              string `json:"serviceId"`
+	ColumnName             string `json:"columnName"`
+	TargetCharacteristicId string `json:"targetCharacteristicId,omitempty"`
+}
+
+// queryLastValues requests the last-value query; the result contains one LastValue per element of body
+func (this *Canary) queryLastValues(token string, body []LastValueRequest) (result []LastValue, err error) {
+	buf := &bytes.Buffer{}
+	err = json.NewEncoder(buf).Encode(body)
+	if err != nil {
+		return result, err
+	}
+	req, err := http.NewRequest(http.MethodPost, this.config.LastValueQueryUrl, buf)
+	if err != nil {
+		this.metrics.UncategorizedErr.Inc()
+		this.config.GetLogger().Error("unable to create last value query http request", "error", err)
+		debug.PrintStack()
+		return result, err
+	}
+	req.Header.Set("Authorization", token)
+	result, _, err = devicemetadata.Do[[]LastValue](req)
+	return result, err
+}
+
 func jsonNormalize(in interface{}) (out interface{}) {
 	temp, _ := json.Marshal(in)
 	json.Unmarshal(temp, &out)
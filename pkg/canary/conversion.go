@@ -0,0 +1,97 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package canary
+
+import (
+	"math"
+	"math/rand"
+	"strconv"
+	"time"
+
+	"github.com/SENERGY-Platform/canary/pkg/devicemetadata"
+)
+
+// testCharacteristicConversion publishes a known sensor value in CanarySensorCharacteristicId
+// and expects it converted to ConnectorConversionTargetCharacteristicId from the last-value query and the device-command api.
+// the expected value is value * ConnectorConversionScale + ConnectorConversionOffset (e.g. 1.8 and 32 for °C to °F).
+func (this *Canary) testCharacteristicConversion(token string, info DeviceInfo, conn Conn) {
+	this.metrics.DeviceRepoRequestCount.Inc()
+	start := time.Now()
+	dt, err, _ := this.devicerepo.ReadDeviceType(info.DeviceTypeId, token)
+	this.metrics.DeviceRepoRequestLatencyMs.Set(float64(time.Since(start).Milliseconds()))
+	if err != nil {
+		this.metrics.DeviceRepoRequestErr.Inc()
+		this.config.GetLogger().Error("unable to read device-type", "error", err)
+		return
+	}
+	serviceId := ""
+	for _, s := range dt.Services {
+		if s.LocalId == devicemetadata.SensorServiceLocalId {
+			serviceId = s.Id
+			break
+		}
+	}
+
+	value := rand.Intn(100)
+	err = this.publishEvent(info, conn, devicemetadata.SensorServiceLocalId, strconv.Itoa(value), this.config.GetConnectorQosLevels()[0])
+	if err != nil {
+		return
+	}
+	expected := float64(value)*this.config.ConnectorConversionScale + this.config.ConnectorConversionOffset
+
+	time.Sleep(this.getChangeGuaranteeDuration())
+
+	this.metrics.ConversionCheckCount.WithLabelValues("last_value").Inc()
+	lastValues, err := this.queryLastValues(token, []LastValueRequest{{
+		DeviceId:               info.Id,
+		ServiceId:              serviceId,
+		ColumnName:             "value",
+		TargetCharacteristicId: this.config.ConnectorConversionTargetCharacteristicId,
+	}})
+	if err != nil {
+		this.metrics.UnexpectedConvertedValueErr.WithLabelValues("last_value").Inc()
+		this.config.GetLogger().Error("unable to read converted last value", "error", err)
+	} else if len(lastValues) != 1 {
+		this.metrics.UnexpectedConvertedValueErr.WithLabelValues("last_value").Inc()
+		this.config.GetLogger().Error("unexpected last value list count", "count", len(lastValues))
+	} else {
+		this.checkConvertedValue("last_value", value, expected, lastValues[0].Value)
+	}
+
+	this.metrics.ConversionCheckCount.WithLabelValues("device_command").Inc()
+	result, err := this.sendDeviceCommand(token, DeviceCommand{
+		FunctionId:       this.config.CanarySensorFunctionId,
+		DeviceId:         info.Id,
+		ServiceId:        serviceId,
+		AspectId:         this.config.CanarySensorAspectId,
+		CharacteristicId: this.config.ConnectorConversionTargetCharacteristicId,
+	})
+	if err != nil {
+		this.metrics.UnexpectedConvertedValueErr.WithLabelValues("device_command").Inc()
+		this.config.GetLogger().Error("unable to read converted value from device-command", "error", err)
+	} else {
+		this.checkConvertedValue("device_command", value, expected, result)
+	}
+}
+
+func (this *Canary) checkConvertedValue(api string, value int, expected float64, actual interface{}) {
+	number, ok := actual.(float64)
+	if !ok || math.Abs(number-expected) > this.config.ConnectorConversionTolerance {
+		this.metrics.UnexpectedConvertedValueErr.WithLabelValues(api).Inc()
+		this.config.GetLogger().Error("unexpected converted value", "api", api, "published", value, "expected", expected, "actual", actual)
+	}
+}
@@ -0,0 +1,112 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package canary
+
+import (
+	"bytes"
+	"encoding/json"
+	"math/rand"
+	"net/http"
+	"reflect"
+	"strconv"
+	"time"
+
+	"github.com/SENERGY-Platform/canary/pkg/devicemetadata"
+)
+
+// testDeviceCommand sends a command to the cmd service of the canary device over the device-command api
+// and expects the response of the simulated device as synchronous result
+func (this *Canary) testDeviceCommand(token string, info DeviceInfo) {
+	this.metrics.DeviceRepoRequestCount.Inc()
+	start := time.Now()
+	dt, err, _ := this.devicerepo.ReadDeviceType(info.DeviceTypeId, token)
+	this.metrics.DeviceRepoRequestLatencyMs.Set(float64(time.Since(start).Milliseconds()))
+	if err != nil {
+		this.metrics.DeviceRepoRequestErr.Inc()
+		this.config.GetLogger().Error("unable to read device-type", "error", err)
+		return
+	}
+	serviceId := ""
+	for _, s := range dt.Services {
+		if s.LocalId == devicemetadata.CmdServiceLocalId {
+			serviceId = s.Id
+			break
+		}
+	}
+
+	this.lastCommandResponse.Store(nil)
+	this.metrics.DeviceCommandCount.Inc()
+	start = time.Now()
+	result, err := this.sendDeviceCommand(token, DeviceCommand{
+		FunctionId:       this.config.CanaryCmdFunctionId,
+		DeviceId:         info.Id,
+		ServiceId:        serviceId,
+		CharacteristicId: this.config.CanaryCmdCharacteristicId,
+		Input:            rand.Intn(100),
+	})
+	this.metrics.DeviceCommandLatencyMs.Set(float64(time.Since(start).Milliseconds()))
+	if err != nil {
+		//responses dropped by the device simulator are counted in canary_device_simulator_dropped_response_count
+		this.metrics.DeviceCommandErr.Inc()
+		this.config.GetLogger().Error("unable to send device-command", "error", err)
+		return
+	}
+
+	response := this.lastCommandResponse.Load()
+	if response == nil {
+		this.metrics.UnexpectedDeviceCommandResponseErr.Inc()
+		this.config.GetLogger().Error("device-command result without response of the canary device", "result", result)
+		return
+	}
+	expected, err := strconv.Atoi(*response)
+	if err != nil || !reflect.DeepEqual(jsonNormalize(result), jsonNormalize(expected)) {
+		this.metrics.UnexpectedDeviceCommandResponseErr.Inc()
+		this.config.GetLogger().Error("unexpected device-command result", "expected", *response, "actual", result)
+	}
+}
+
+type DeviceCommand struct {
+	FunctionId       string      `json:"function_id"`
+	DeviceId         string      `json:"device_id,omitempty"`
+	ServiceId        string      `json:"service_id,omitempty"`
+	AspectId         string      `json:"aspect_id,omitempty"`
+	CharacteristicId string      `json:"characteristic_id,omitempty"`
+	Input            interface{} `json:"input,omitempty"`
+}
+
+// sendDeviceCommand sends a single command to the device-command api and returns its result
+func (this *Canary) sendDeviceCommand(token string, cmd DeviceCommand) (result interface{}, err error) {
+	buf := &bytes.Buffer{}
+	err = json.NewEncoder(buf).Encode(cmd)
+	if err != nil {
+		return result, err
+	}
+	req, err := http.NewRequest(http.MethodPost, this.config.DeviceCommandUrl+"/commands", buf)
+	if err != nil {
+		return result, err
+	}
+	req.Header.Set("Authorization", token)
+	result, _, err = devicemetadata.Do[interface{}](req)
+	if err != nil {
+		return result, err
+	}
+	//results of single commands may be wrapped in a list
+	if list, ok := result.([]interface{}); ok && len(list) == 1 {
+		result = list[0]
+	}
+	return result, nil
+}
@@ -0,0 +1,63 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package canary
+
+import (
+	"strings"
+	"time"
+
+	"github.com/SENERGY-Platform/canary/pkg/configuration"
+)
+
+// testDeviceErrorNotification publishes a device error and expects a notification containing the error message.
+// the matching notifications are deleted afterward.
+func (this *Canary) testDeviceErrorNotification(token string, info DeviceInfo, conn Conn) {
+	broker := conn.GetBroker()
+	text := "canary-device-error-" + time.Now().String()
+	topic := "error/device/" + info.LocalId
+	if this.config.TopicsWithOwner {
+		topic = "error/device/" + info.OwnerId + "/" + info.LocalId
+	}
+
+	this.metrics.DeviceErrorCount.Inc()
+	reasonCode, err := conn.Publish(topic, configuration.DefaultConnectorQos, []byte(text))
+	this.countReasonCode(broker, "puback", reasonCode)
+	if err != nil {
+		this.config.GetLogger().Error("unable to publish device error", "error", err, "reason_code", formatReasonCode(reasonCode), "broker", broker.Name)
+		this.metrics.DeviceErrorPublishErr.Inc()
+		return
+	}
+
+	time.Sleep(this.getChangeGuaranteeDuration())
+
+	notifications, err := this.getNotifications(token)
+	if err != nil {
+		return
+	}
+	ids := []string{}
+	for _, n := range notifications {
+		if strings.Contains(n.Message, text) {
+			ids = append(ids, n.Id)
+		}
+	}
+	if len(ids) == 0 {
+		this.metrics.UnexpectedDeviceErrorNotificationErr.Inc()
+		this.config.GetLogger().Error("no notification found for device error", "topic", topic, "broker", broker.Name)
+		return
+	}
+	this.deleteNotifications(token, ids)
+}
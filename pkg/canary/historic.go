@@ -0,0 +1,66 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package canary
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+
+	"github.com/SENERGY-Platform/canary/pkg/devicemetadata"
+)
+
+type QueriesRequestElement struct {
+	DeviceId         string                        `json:"deviceId"`
+	ServiceId        string                        `json:"serviceId"`
+	Time             QueriesRequestElementTime     `json:"time"`
+	Limit            int                           `json:"limit,omitempty"`
+	Columns          []QueriesRequestElementColumn `json:"columns"`
+	GroupTime        string                        `json:"groupTime,omitempty"`
+	OrderColumnIndex int                           `json:"orderColumnIndex"`
+	OrderDirection   string                        `json:"orderDirection,omitempty"`
+}
+
+type QueriesRequestElementTime struct {
+	Last  string `json:"last,omitempty"`
+	Start string `json:"start,omitempty"`
+	End   string `json:"end,omitempty"`
+}
+
+type QueriesRequestElementColumn struct {
+	Name      string `json:"name"`
+	GroupType string `json:"groupType,omitempty"`
+}
+
+// HistoricRows contains one row per point in time; the first element of a row is the time, followed by one element per requested column
+type HistoricRows = [][]interface{}
+
+// queryHistoricData requests the historic data query; the result contains one HistoricRows per element of body
+func (this *Canary) queryHistoricData(token string, body []QueriesRequestElement) (result []HistoricRows, err error) {
+	buf := &bytes.Buffer{}
+	err = json.NewEncoder(buf).Encode(body)
+	if err != nil {
+		return result, err
+	}
+	req, err := http.NewRequest(http.MethodPost, this.config.HistoricQueryUrl, buf)
+	if err != nil {
+		return result, err
+	}
+	req.Header.Set("Authorization", token)
+	result, _, err = devicemetadata.Do[[]HistoricRows](req)
+	return result, err
+}
@@ -0,0 +1,153 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package canary
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/SENERGY-Platform/canary/pkg/devicemetadata"
+)
+
+const historicQueryGroupTime = "1m"
+const historicQueryLimit = 10000
+
+// testHistoricQuery queries the sensor values since runStart, raw and as mean per minute.
+// the published values have to be part of the raw values and the means have to match means computed from the raw values.
+// values of earlier runs (e.g. of the burst check) are excluded by the time window; reaching historicQueryLimit is an error.
+func (this *Canary) testHistoricQuery(token string, info DeviceInfo, published []int, runStart time.Time) {
+	this.metrics.DeviceRepoRequestCount.Inc()
+	start := time.Now()
+	dt, err, _ := this.devicerepo.ReadDeviceType(info.DeviceTypeId, token)
+	this.metrics.DeviceRepoRequestLatencyMs.Set(float64(time.Since(start).Milliseconds()))
+	if err != nil {
+		this.metrics.DeviceRepoRequestErr.Inc()
+		this.config.GetLogger().Error("unable to read device-type", "error", err)
+		return
+	}
+	serviceId := ""
+	for _, s := range dt.Services {
+		if s.LocalId == devicemetadata.SensorServiceLocalId {
+			serviceId = s.Id
+			break
+		}
+	}
+
+	window := QueriesRequestElementTime{
+		Start: runStart.Add(-time.Second).UTC().Format(time.RFC3339Nano),
+		End:   time.Now().Add(time.Second).UTC().Format(time.RFC3339Nano),
+	}
+	this.metrics.HistoricQueryCount.Inc()
+	start = time.Now()
+	result, err := this.queryHistoricData(token, []QueriesRequestElement{
+		{
+			DeviceId:         info.Id,
+			ServiceId:        serviceId,
+			Time:             window,
+			Limit:            historicQueryLimit,
+			Columns:          []QueriesRequestElementColumn{{Name: "value"}},
+			OrderColumnIndex: 0,
+			OrderDirection:   "desc",
+		},
+		{
+			DeviceId:         info.Id,
+			ServiceId:        serviceId,
+			Time:             window,
+			Columns:          []QueriesRequestElementColumn{{Name: "value", GroupType: "mean"}},
+			GroupTime:        historicQueryGroupTime,
+			OrderColumnIndex: 0,
+			OrderDirection:   "asc",
+		},
+	})
+	this.metrics.HistoricQueryLatencyMs.Set(float64(time.Since(start).Milliseconds()))
+	if err != nil || len(result) != 2 {
+		this.metrics.HistoricQueryErr.Inc()
+		this.config.GetLogger().Error("unable to query historic data", "error", err, "count", len(result))
+		return
+	}
+	raw, aggregated := result[0], result[1]
+
+	stored := map[float64]bool{}
+	for _, row := range raw {
+		if len(row) > 1 {
+			if value, ok := row[1].(float64); ok {
+				stored[value] = true
+			}
+		}
+	}
+	for _, value := range published {
+		if !stored[float64(value)] {
+			this.metrics.UnexpectedHistoricDataErr.Inc()
+			this.config.GetLogger().Error("published value missing in historic data", "value", value)
+		}
+	}
+
+	if len(raw) >= historicQueryLimit {
+		this.metrics.HistoricQueryErr.Inc()
+		this.config.GetLogger().Error("historic data limit reached, unable to check aggregates", "limit", historicQueryLimit)
+		return
+	}
+	groupTime, _ := time.ParseDuration(historicQueryGroupTime)
+	expected := meanPerBucket(raw, groupTime)
+	for i, row := range aggregated {
+		//the first bucket may be cut differently by both queries
+		if i == 0 || len(row) < 2 || row[1] == nil {
+			continue
+		}
+		bucket, err := time.Parse(time.RFC3339Nano, fmt.Sprint(row[0]))
+		if err != nil {
+			this.metrics.UnexpectedHistoricAggregateErr.Inc()
+			this.config.GetLogger().Error("unable to parse historic aggregate time", "error", err)
+			continue
+		}
+		actual, ok := row[1].(float64)
+		mean, found := expected[bucket.UTC()]
+		if !ok || !found || math.Abs(actual-mean) > math.Abs(mean)*1e-6 {
+			this.metrics.UnexpectedHistoricAggregateErr.Inc()
+			this.config.GetLogger().Error("unexpected historic mean", "bucket", bucket, "expected", mean, "actual", row[1])
+		}
+	}
+}
+
+// meanPerBucket computes the mean of the values in rows per time bucket of the size groupTime.
+// the buckets are identified by their UTC start time.
+func meanPerBucket(rows HistoricRows, groupTime time.Duration) map[time.Time]float64 {
+	sums := map[time.Time]float64{}
+	counts := map[time.Time]int{}
+	for _, row := range rows {
+		if len(row) < 2 {
+			continue
+		}
+		value, ok := row[1].(float64)
+		if !ok {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339Nano, fmt.Sprint(row[0]))
+		if err != nil {
+			continue
+		}
+		bucket := t.UTC().Truncate(groupTime)
+		sums[bucket] += value
+		counts[bucket]++
+	}
+	result := map[time.Time]float64{}
+	for bucket, sum := range sums {
+		result[bucket] = sum / float64(counts[bucket])
+	}
+	return result
+}
@@ -0,0 +1,42 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package canary
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMeanPerBucket(t *testing.T) {
+	rows := HistoricRows{
+		{"2026-01-01T00:00:10Z", 1.0},
+		{"2026-01-01T00:00:50Z", 2.0},
+		{"2026-01-01T00:01:00Z", 10.0},
+		{"2026-01-01T01:01:30+01:00", 20.0},
+		{"2026-01-01T00:02:00Z", nil},
+	}
+	means := meanPerBucket(rows, time.Minute)
+	if len(means) != 2 {
+		t.Error("unexpected bucket count", means)
+	}
+	if mean := means[time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)]; mean != 1.5 {
+		t.Error("unexpected mean", mean)
+	}
+	if mean := means[time.Date(2026, 1, 1, 0, 1, 0, 0, time.UTC)]; mean != 15 {
+		t.Error("unexpected mean", mean)
+	}
+}
@@ -19,6 +19,8 @@ package canary
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
+	"io"
 	"net/http"
 	"net/url"
 	"runtime/debug"
@@ -89,11 +91,13 @@ func (this *Canary) listCanaryHubs(token string) (hubs []HubInfo, err error) {
 }
 
 func (this *Canary) createCanaryHub(token string, device DeviceInfo) (hubId string, err error) {
-	hub := HubInfo{
+	return this.createHub(token, HubInfo{
 		Name:           this.config.CanaryHubName,
 		DeviceLocalIds: []string{device.LocalId},
-	}
+	})
+}
 
+func (this *Canary) createHub(token string, hub HubInfo) (hubId string, err error) {
 	buf := &bytes.Buffer{}
 	err = json.NewEncoder(buf).Encode(hub)
 	if err != nil {
@@ -146,3 +150,28 @@ func (this *Canary) updateCanaryHub(token string, hubId string, device DeviceInf
 	time.Sleep(this.getChangeGuaranteeDuration())
 	return err
 }
+
+func (this *Canary) deleteHub(token string, hubId string) (err error) {
+	this.metrics.DeviceMetaUpdateCount.Inc()
+	req, err := http.NewRequest(http.MethodDelete, this.config.DeviceManagerUrl+"/hubs/"+url.PathEscape(hubId)+"?wait=true", nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", token)
+	start := time.Now()
+	resp, err := http.DefaultClient.Do(req)
+	this.metrics.DeviceMetaUpdateLatencyMs.Set(float64(time.Since(start).Milliseconds()))
+	if err != nil {
+		this.metrics.DeviceMetaUpdateErr.Inc()
+		this.config.GetLogger().Error("unable to delete hub", "error", err, "hub", hubId)
+		return err
+	}
+	defer resp.Body.Close()
+	respMsg, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode >= 300 {
+		this.metrics.DeviceMetaUpdateErr.Inc()
+		this.config.GetLogger().Error("unexpected response status from device-manager", "status-code", resp.StatusCode, "error", string(respMsg), "hub", hubId)
+		return errors.New("unexpected response status from device-manager " + resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,222 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package canary
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	_ "crypto/sha256"
+	_ "crypto/sha512"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"slices"
+	"strings"
+	"time"
+)
+
+type JsonWebKeySet struct {
+	Keys []JsonWebKey `json:"keys"`
+}
+
+type JsonWebKey struct {
+	Kid string `json:"kid"`
+	Kty string `json:"kty"`
+	Alg string `json:"alg"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+type JwtClaims = map[string]interface{}
+
+func (this JsonWebKey) publicKey() (crypto.PublicKey, error) {
+	switch this.Kty {
+	case "RSA":
+		n, err := base64.RawURLEncoding.DecodeString(this.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := base64.RawURLEncoding.DecodeString(this.E)
+		if err != nil {
+			return nil, err
+		}
+		return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch this.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, errors.New("unsupported curve: " + this.Crv)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(this.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := base64.RawURLEncoding.DecodeString(this.Y)
+		if err != nil {
+			return nil, err
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}, nil
+	default:
+		return nil, errors.New("unsupported key type: " + this.Kty)
+	}
+}
+
+// verifyJwt checks the signature of token with the matching key of keys and returns the tokens claims.
+// supported algorithms are RS*, PS* and ES*.
+func verifyJwt(token string, keys JsonWebKeySet) (claims JwtClaims, err error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return claims, errors.New("invalid jwt format")
+	}
+	header := struct {
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+	}{}
+	err = decodeJwtPart(parts[0], &header)
+	if err != nil {
+		return claims, fmt.Errorf("invalid jwt header: %w", err)
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return claims, fmt.Errorf("invalid jwt signature encoding: %w", err)
+	}
+
+	var key *JsonWebKey
+	for _, k := range keys.Keys {
+		if k.Kid == header.Kid && (k.Use == "" || k.Use == "sig") {
+			key = &k
+			break
+		}
+	}
+	if key == nil {
+		return claims, errors.New("no matching key found for kid " + header.Kid)
+	}
+	publicKey, err := key.publicKey()
+	if err != nil {
+		return claims, err
+	}
+
+	if len(header.Alg) != 5 {
+		return claims, errors.New("unsupported jwt algorithm: " + header.Alg)
+	}
+	var hash crypto.Hash
+	switch header.Alg[2:] {
+	case "256":
+		hash = crypto.SHA256
+	case "384":
+		hash = crypto.SHA384
+	case "512":
+		hash = crypto.SHA512
+	default:
+		return claims, errors.New("unsupported jwt algorithm: " + header.Alg)
+	}
+	hasher := hash.New()
+	hasher.Write([]byte(parts[0] + "." + parts[1]))
+	digest := hasher.Sum(nil)
+
+	switch {
+	case strings.HasPrefix(header.Alg, "RS") || strings.HasPrefix(header.Alg, "PS"):
+		rsaKey, ok := publicKey.(*rsa.PublicKey)
+		if !ok {
+			return claims, errors.New("key type does not match jwt algorithm " + header.Alg)
+		}
+		if strings.HasPrefix(header.Alg, "RS") {
+			err = rsa.VerifyPKCS1v15(rsaKey, hash, digest, signature)
+		} else {
+			err = rsa.VerifyPSS(rsaKey, hash, digest, signature, nil)
+		}
+		if err != nil {
+			return claims, fmt.Errorf("invalid jwt signature: %w", err)
+		}
+	case strings.HasPrefix(header.Alg, "ES"):
+		ecKey, ok := publicKey.(*ecdsa.PublicKey)
+		if !ok || len(signature)%2 != 0 {
+			return claims, errors.New("key type does not match jwt algorithm " + header.Alg)
+		}
+		r := new(big.Int).SetBytes(signature[:len(signature)/2])
+		s := new(big.Int).SetBytes(signature[len(signature)/2:])
+		if !ecdsa.Verify(ecKey, digest, r, s) {
+			return claims, errors.New("invalid jwt signature")
+		}
+	default:
+		return claims, errors.New("unsupported jwt algorithm: " + header.Alg)
+	}
+
+	err = decodeJwtPart(parts[1], &claims)
+	if err != nil {
+		return claims, fmt.Errorf("invalid jwt payload: %w", err)
+	}
+	return claims, nil
+}
+
+func decodeJwtPart(part string, result interface{}) error {
+	b, err := base64.RawURLEncoding.DecodeString(part)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, result)
+}
+
+// checkJwtClaims checks issuer, expiration, authorized party, realm roles and additional expected claims
+func checkJwtClaims(claims JwtClaims, issuer string, clientId string, expectedRoles []string, expectedClaims map[string]string, now time.Time) error {
+	if iss, _ := claims["iss"].(string); iss != issuer {
+		return fmt.Errorf("unexpected issuer: expected %v, actual %v", issuer, iss)
+	}
+	exp, ok := claims["exp"].(float64)
+	if !ok {
+		return errors.New("missing exp claim")
+	}
+	if time.Unix(int64(exp), 0).Before(now) {
+		return errors.New("token already expired")
+	}
+	if azp, ok := claims["azp"].(string); ok && clientId != "" && azp != clientId {
+		return fmt.Errorf("unexpected authorized party: expected %v, actual %v", clientId, azp)
+	}
+	roles := []string{}
+	if realmAccess, ok := claims["realm_access"].(map[string]interface{}); ok {
+		if list, ok := realmAccess["roles"].([]interface{}); ok {
+			for _, role := range list {
+				roles = append(roles, fmt.Sprint(role))
+			}
+		}
+	}
+	for _, role := range expectedRoles {
+		if !slices.Contains(roles, role) {
+			return fmt.Errorf("missing role %v in %v", role, roles)
+		}
+	}
+	for key, expected := range expectedClaims {
+		if actual := fmt.Sprint(claims[key]); actual != expected {
+			return fmt.Errorf("unexpected claim %v: expected %v, actual %v", key, expected, actual)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,103 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package canary
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestVerifyJwt(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	keys := JsonWebKeySet{Keys: []JsonWebKey{{
+		Kid: "test-kid",
+		Kty: "RSA",
+		Use: "sig",
+		N:   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+		E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+	}}}
+
+	now := time.Now()
+	token := signTestJwt(t, key, "test-kid", JwtClaims{
+		"iss":                "https://auth/auth/realms/master",
+		"azp":                "frontend",
+		"exp":                now.Add(time.Minute).Unix(),
+		"iat":                now.Unix(),
+		"realm_access":       map[string]interface{}{"roles": []string{"user", "developer"}},
+		"preferred_username": "canary",
+	})
+
+	claims, err := verifyJwt(token, keys)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	err = checkJwtClaims(claims, "https://auth/auth/realms/master", "frontend", []string{"user"}, map[string]string{"preferred_username": "canary"}, now)
+	if err != nil {
+		t.Error(err)
+	}
+	err = checkJwtClaims(claims, "https://auth/auth/realms/master", "frontend", []string{"admin"}, nil, now)
+	if err == nil {
+		t.Error("expected missing role error")
+	}
+	err = checkJwtClaims(claims, "https://other/auth/realms/master", "frontend", nil, nil, now)
+	if err == nil {
+		t.Error("expected issuer error")
+	}
+	err = checkJwtClaims(claims, "https://auth/auth/realms/master", "frontend", nil, nil, now.Add(time.Hour))
+	if err == nil {
+		t.Error("expected expiration error")
+	}
+
+	parts := strings.Split(token, ".")
+	tamperedPayload, _ := json.Marshal(JwtClaims{"iss": "https://auth/auth/realms/master", "realm_access": map[string]interface{}{"roles": []string{"admin"}}})
+	tampered := parts[0] + "." + base64.RawURLEncoding.EncodeToString(tamperedPayload) + "." + parts[2]
+	_, err = verifyJwt(tampered, keys)
+	if err == nil {
+		t.Error("expected signature error")
+	}
+
+	_, err = verifyJwt(signTestJwt(t, key, "unknown-kid", JwtClaims{}), keys)
+	if err == nil {
+		t.Error("expected unknown key error")
+	}
+}
+
+func signTestJwt(t *testing.T, key *rsa.PrivateKey, kid string, claims JwtClaims) string {
+	header, _ := json.Marshal(map[string]string{"alg": "RS256", "typ": "JWT", "kid": kid})
+	payload, _ := json.Marshal(claims)
+	unsigned := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	digest := sha256.Sum256([]byte(unsigned))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	return unsigned + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
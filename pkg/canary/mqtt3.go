@@ -0,0 +1,232 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package canary
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/SENERGY-Platform/canary/pkg/configuration"
+	paho "github.com/eclipse/paho.mqtt.golang"
+	"github.com/eclipse/paho.mqtt.golang/packets"
+)
+
+// Mqtt3Conn implements Conn with mqtt 3.1.1
+type Mqtt3Conn struct {
+	client        paho.Client
+	broker        configuration.ConnectorBroker
+	mux           sync.Mutex
+	subscriptions map[string]mqtt3Subscription
+	lostAt        time.Time
+	netConn       net.Conn
+	killed        bool
+	expectLoss    bool
+	lostCount     int
+}
+
+type mqtt3Subscription struct {
+	qos     byte
+	handler paho.MessageHandler
+}
+
+func (this *Canary) connectMqtt3(hubId string, broker configuration.ConnectorBroker, tlsConf *tls.Config) (conn *Mqtt3Conn, reasonCode int, err error) {
+	brokerUrl, err := broker.GetUrl()
+	if err != nil {
+		return conn, NoReasonCode, fmt.Errorf("invalid broker url: %w", err)
+	}
+
+	conn = &Mqtt3Conn{broker: broker, subscriptions: map[string]mqtt3Subscription{}}
+
+	options := paho.NewClientOptions().
+		SetClientID(hubId).
+		SetAutoReconnect(true).
+		SetCleanSession(true).
+		AddBroker(brokerUrl).
+		SetConnectionLostHandler(func(c paho.Client, err error) {
+			conn.mux.Lock()
+			expected := conn.killed || conn.expectLoss
+			conn.lostAt = time.Now()
+			conn.lostCount++
+			conn.mux.Unlock()
+			if expected {
+				return
+			}
+			this.onConnectionLost(broker, err)
+		}).
+		SetReconnectingHandler(func(c paho.Client, options *paho.ClientOptions) {
+			this.onReconnecting(broker)
+		}).
+		SetOnConnectHandler(func(c paho.Client) {
+			conn.mux.Lock()
+			lostAt := conn.lostAt
+			conn.lostAt = time.Time{}
+			conn.mux.Unlock()
+			if !lostAt.IsZero() {
+				this.onReconnected(broker, time.Since(lostAt))
+				//the session is clean after a reconnect
+				go func() {
+					err := conn.resubscribe()
+					if err != nil {
+						this.config.GetLogger().Error("unable to resubscribe", "error", err, "broker", broker.Name)
+						this.metrics.ConnectorSubscribeErr.WithLabelValues(brokerLabels(broker)...).Inc()
+					}
+				}()
+			}
+		})
+
+	if broker.GetTransport() == configuration.TransportWebsocket {
+		options = options.SetHTTPHeaders(broker.GetHeaders())
+	} else {
+		//keep the network connection to be able to kill it
+		options = options.SetCustomOpenConnectionFn(func(uri *url.URL, options paho.ClientOptions) (net.Conn, error) {
+			conn.mux.Lock()
+			defer conn.mux.Unlock()
+			if conn.killed {
+				return nil, errors.New("connection killed")
+			}
+			netConn, err := dialMqtt(uri.String(), options.TLSConfig)
+			if err != nil {
+				return nil, err
+			}
+			conn.netConn = netConn
+			return netConn, nil
+		})
+	}
+
+	if tlsConf != nil {
+		options = options.SetTLSConfig(tlsConf)
+	} else {
+		options = options.SetUsername(broker.Username).SetPassword(broker.Password)
+	}
+
+	conn.client = paho.NewClient(options)
+	token := conn.client.Connect()
+	token.Wait()
+	reasonCode = NoReasonCode
+	if connectToken, ok := token.(*paho.ConnectToken); ok {
+		code := connectToken.ReturnCode()
+		//paho uses codes above ErrRefusedNotAuthorised for errors without connack
+		if code <= packets.ErrRefusedNotAuthorised && (code != packets.Accepted || token.Error() == nil) {
+			reasonCode = int(code)
+		}
+	}
+	return conn, reasonCode, token.Error()
+}
+
+func (this *Mqtt3Conn) GetBroker() configuration.ConnectorBroker {
+	return this.broker
+}
+
+// IsConnected is true while the connection is established or being reestablished
+func (this *Mqtt3Conn) IsConnected() bool {
+	return this.client.IsConnected()
+}
+
+func (this *Mqtt3Conn) Subscribe(topic string, qos byte, handler func(topic string, payload []byte)) (reasonCode int, err error) {
+	subscription := mqtt3Subscription{qos: qos, handler: func(c paho.Client, message paho.Message) {
+		handler(message.Topic(), message.Payload())
+	}}
+	reasonCode, err = this.subscribe(topic, subscription)
+	if err != nil {
+		return reasonCode, err
+	}
+	this.mux.Lock()
+	defer this.mux.Unlock()
+	this.subscriptions[topic] = subscription
+	return reasonCode, nil
+}
+
+func (this *Mqtt3Conn) subscribe(topic string, subscription mqtt3Subscription) (reasonCode int, err error) {
+	token := this.client.Subscribe(topic, subscription.qos, subscription.handler)
+	if !token.WaitTimeout(mqttTimeout) {
+		return NoReasonCode, errors.New("timeout while waiting for suback")
+	}
+	if token.Error() != nil {
+		return NoReasonCode, token.Error()
+	}
+	code, ok := token.(*paho.SubscribeToken).Result()[topic]
+	if !ok {
+		return NoReasonCode, nil
+	}
+	if code >= 0x80 {
+		return int(code), fmt.Errorf("subscription to %v refused", topic)
+	}
+	return int(code), nil
+}
+
+func (this *Mqtt3Conn) resubscribe() error {
+	this.mux.Lock()
+	subscriptions := map[string]mqtt3Subscription{}
+	for topic, subscription := range this.subscriptions {
+		subscriptions[topic] = subscription
+	}
+	this.mux.Unlock()
+	for topic, subscription := range subscriptions {
+		_, err := this.subscribe(topic, subscription)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Publish returns NoReasonCode because mqtt 3.1.1 acknowledgements carry no reason code
+func (this *Mqtt3Conn) Publish(topic string, qos byte, payload []byte) (reasonCode int, err error) {
+	token := this.client.Publish(topic, qos, false, payload)
+	if !token.WaitTimeout(mqttTimeout) {
+		return NoReasonCode, errors.New("timeout while waiting for publish acknowledgement")
+	}
+	return NoReasonCode, token.Error()
+}
+
+// Kill closes the network connection without disconnect packet; websocket connections are not supported
+func (this *Mqtt3Conn) Kill() error {
+	this.mux.Lock()
+	netConn := this.netConn
+	if netConn != nil {
+		this.killed = true
+	}
+	this.mux.Unlock()
+	if netConn == nil {
+		return errors.New("kill is not supported for " + this.broker.GetTransport() + " connections")
+	}
+	err := netConn.Close()
+	//stop auto reconnect; the disconnect packet can no longer be sent
+	this.client.Disconnect(0)
+	return err
+}
+
+func (this *Mqtt3Conn) ExpectLoss() {
+	this.mux.Lock()
+	defer this.mux.Unlock()
+	this.expectLoss = true
+}
+
+func (this *Mqtt3Conn) LostCount() int {
+	this.mux.Lock()
+	defer this.mux.Unlock()
+	return this.lostCount
+}
+
+func (this *Mqtt3Conn) Disconnect() {
+	this.client.Disconnect(250)
+}
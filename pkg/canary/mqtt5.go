@@ -0,0 +1,268 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package canary
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/SENERGY-Platform/canary/pkg/configuration"
+	"github.com/eclipse/paho.golang/packets"
+	paho5 "github.com/eclipse/paho.golang/paho"
+)
+
+// mqttTimeout limits the wait for dial and acknowledgement packets
+const mqttTimeout = 30 * time.Second
+
+const mqtt5KeepAlive = 30
+
+const mqtt5MaxReconnectDelay = time.Minute
+
+// Mqtt5Conn implements Conn with mqtt 5.
+// paho.golang does not reconnect by itself; lost connections are reestablished by reconnect()
+type Mqtt5Conn struct {
+	canary        *Canary
+	hubId         string
+	broker        configuration.ConnectorBroker
+	tlsConf       *tls.Config
+	router        *paho5.StandardRouter
+	mux           sync.Mutex
+	client        *paho5.Client
+	netConn       net.Conn
+	subscriptions map[string]byte
+	closed        bool
+	lostAt        time.Time
+	expectLoss    bool
+	lostCount     int
+}
+
+func (this *Canary) connectMqtt5(hubId string, broker configuration.ConnectorBroker, tlsConf *tls.Config) (conn *Mqtt5Conn, reasonCode int, err error) {
+	if broker.GetTransport() == configuration.TransportWebsocket {
+		return conn, NoReasonCode, errors.New("mqtt 5 is only supported for tcp brokers")
+	}
+	conn = &Mqtt5Conn{
+		canary:        this,
+		hubId:         hubId,
+		broker:        broker,
+		tlsConf:       tlsConf,
+		router:        paho5.NewStandardRouter(),
+		subscriptions: map[string]byte{},
+	}
+	reasonCode, err = conn.connect()
+	return conn, reasonCode, err
+}
+
+// connect establishes a new connection, replaces the current client and restores the subscriptions
+func (this *Mqtt5Conn) connect() (reasonCode int, err error) {
+	netConn, err := dialMqtt(this.broker.Url, this.tlsConf)
+	if err != nil {
+		return NoReasonCode, err
+	}
+
+	var client *paho5.Client
+	client = paho5.NewClient(paho5.ClientConfig{
+		ClientID: this.hubId,
+		Conn:     packets.NewThreadSafeConn(netConn),
+		Router:   this.router,
+		OnServerDisconnect: func(disconnect *paho5.Disconnect) {
+			this.onConnectionLost(client, errors.New("server closed connection with reason code "+formatReasonCode(int(disconnect.ReasonCode))))
+		},
+		OnClientError: func(err error) {
+			this.onConnectionLost(client, err)
+		},
+	})
+
+	connect := &paho5.Connect{
+		ClientID:   this.hubId,
+		CleanStart: true,
+		KeepAlive:  mqtt5KeepAlive,
+	}
+	if this.tlsConf == nil {
+		connect.Username = this.broker.Username
+		connect.UsernameFlag = this.broker.Username != ""
+		connect.Password = []byte(this.broker.Password)
+		connect.PasswordFlag = this.broker.Password != ""
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), mqttTimeout)
+	defer cancel()
+	connack, err := client.Connect(ctx, connect)
+	reasonCode = NoReasonCode
+	if connack != nil {
+		reasonCode = int(connack.ReasonCode)
+	}
+	if err != nil {
+		netConn.Close()
+		return reasonCode, err
+	}
+
+	this.mux.Lock()
+	if this.closed {
+		this.mux.Unlock()
+		client.Disconnect(&paho5.Disconnect{ReasonCode: 0})
+		return reasonCode, errors.New("connection closed")
+	}
+	this.client = client
+	this.netConn = netConn
+	subscriptions := map[string]byte{}
+	for topic, qos := range this.subscriptions {
+		subscriptions[topic] = qos
+	}
+	this.mux.Unlock()
+
+	for topic, qos := range subscriptions {
+		_, err := this.subscribe(topic, qos)
+		if err != nil {
+			this.canary.config.GetLogger().Error("unable to resubscribe", "error", err, "broker", this.broker.Name)
+			this.canary.metrics.ConnectorSubscribeErr.WithLabelValues(brokerLabels(this.broker)...).Inc()
+		}
+	}
+	return reasonCode, nil
+}
+
+func (this *Mqtt5Conn) onConnectionLost(client *paho5.Client, err error) {
+	this.mux.Lock()
+	if this.closed || client != this.client || !this.lostAt.IsZero() {
+		this.mux.Unlock()
+		return
+	}
+	this.lostAt = time.Now()
+	this.lostCount++
+	expected := this.expectLoss
+	this.mux.Unlock()
+	if !expected {
+		this.canary.onConnectionLost(this.broker, err)
+	}
+	go this.reconnect()
+}
+
+func (this *Mqtt5Conn) reconnect() {
+	delay := time.Second
+	for {
+		time.Sleep(delay)
+		this.mux.Lock()
+		closed := this.closed
+		this.mux.Unlock()
+		if closed {
+			return
+		}
+		this.canary.onReconnecting(this.broker)
+		_, err := this.connect()
+		if err == nil {
+			this.mux.Lock()
+			lostAt := this.lostAt
+			this.lostAt = time.Time{}
+			this.mux.Unlock()
+			this.canary.onReconnected(this.broker, time.Since(lostAt))
+			return
+		}
+		this.canary.config.GetLogger().Warn("unable to reconnect", "error", err, "broker", this.broker.Name)
+		delay = min(2*delay, mqtt5MaxReconnectDelay)
+	}
+}
+
+func (this *Mqtt5Conn) getClient() *paho5.Client {
+	this.mux.Lock()
+	defer this.mux.Unlock()
+	return this.client
+}
+
+func (this *Mqtt5Conn) GetBroker() configuration.ConnectorBroker {
+	return this.broker
+}
+
+// IsConnected is true while the connection is established or being reestablished
+func (this *Mqtt5Conn) IsConnected() bool {
+	this.mux.Lock()
+	defer this.mux.Unlock()
+	return !this.closed
+}
+
+func (this *Mqtt5Conn) Subscribe(topic string, qos byte, handler func(topic string, payload []byte)) (reasonCode int, err error) {
+	//the router appends handlers; a new subscription replaces the handler like in mqtt 3.1.1
+	this.router.UnregisterHandler(topic)
+	this.router.RegisterHandler(topic, func(publish *paho5.Publish) {
+		handler(publish.Topic, publish.Payload)
+	})
+	reasonCode, err = this.subscribe(topic, qos)
+	if err != nil {
+		this.router.UnregisterHandler(topic)
+		return reasonCode, err
+	}
+	this.mux.Lock()
+	defer this.mux.Unlock()
+	this.subscriptions[topic] = qos
+	return reasonCode, nil
+}
+
+func (this *Mqtt5Conn) subscribe(topic string, qos byte) (reasonCode int, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), mqttTimeout)
+	defer cancel()
+	suback, err := this.getClient().Subscribe(ctx, &paho5.Subscribe{
+		Subscriptions: []paho5.SubscribeOptions{{Topic: topic, QoS: qos}},
+	})
+	reasonCode = NoReasonCode
+	if suback != nil && len(suback.Reasons) > 0 {
+		reasonCode = int(suback.Reasons[0])
+	}
+	return reasonCode, err
+}
+
+// Publish returns the reason code of the puback (qos 1) or pubrec (qos 2)
+func (this *Mqtt5Conn) Publish(topic string, qos byte, payload []byte) (reasonCode int, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), mqttTimeout)
+	defer cancel()
+	resp, err := this.getClient().Publish(ctx, &paho5.Publish{Topic: topic, QoS: qos, Payload: payload})
+	reasonCode = NoReasonCode
+	if resp != nil && qos > 0 {
+		reasonCode = int(resp.ReasonCode)
+	}
+	return reasonCode, err
+}
+
+// Kill closes the network connection without disconnect packet
+func (this *Mqtt5Conn) Kill() error {
+	this.mux.Lock()
+	this.closed = true
+	netConn := this.netConn
+	this.mux.Unlock()
+	return netConn.Close()
+}
+
+func (this *Mqtt5Conn) ExpectLoss() {
+	this.mux.Lock()
+	defer this.mux.Unlock()
+	this.expectLoss = true
+}
+
+func (this *Mqtt5Conn) LostCount() int {
+	this.mux.Lock()
+	defer this.mux.Unlock()
+	return this.lostCount
+}
+
+func (this *Mqtt5Conn) Disconnect() {
+	this.mux.Lock()
+	this.closed = true
+	client := this.client
+	this.mux.Unlock()
+	client.Disconnect(&paho5.Disconnect{ReasonCode: 0})
+}
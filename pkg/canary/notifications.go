@@ -27,6 +27,11 @@ import (
 	"time"
 )
 
+// notificationTitle marks the notifications sent by testNotification
+const notificationTitle = "Canary-Test-Message"
+
+// testNotification sends and reads a notification.
+// only notifications of this check are deleted; other checks (e.g. device errors) read their notifications concurrently.
 func (this *Canary) testNotification(wg *sync.WaitGroup, token string) {
 	wg.Add(1)
 	go func() {
@@ -49,7 +54,10 @@ func (this *Canary) testNotification(wg *sync.WaitGroup, token string) {
 		ids := []string{}
 		found := false
 		for _, n := range notifications {
-			ids = append(ids, n.Id)
+			//includes leftovers of earlier runs
+			if n.Title == notificationTitle {
+				ids = append(ids, n.Id)
+			}
 			if n.Message == text {
 				found = true
 			}
@@ -60,6 +68,9 @@ func (this *Canary) testNotification(wg *sync.WaitGroup, token string) {
 			this.config.GetLogger().Error("UnexpectedNotificationStateErr")
 		}
 
+		if len(ids) == 0 {
+			return
+		}
 		err = this.deleteNotifications(token, ids)
 		if err != nil {
 			return
@@ -91,7 +102,7 @@ type NotificationList struct {
 
 func (this *Canary) sendNotification(token string, text string) (err error) {
 	message := Message{
-		Title:   "Canary-Test-Message",
+		Title:   notificationTitle,
 		Message: text,
 	}
 
@@ -0,0 +1,104 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package canary
+
+import (
+	"time"
+
+	"github.com/SENERGY-Platform/canary/pkg/configuration"
+)
+
+const DisconnectClean = "clean"
+const DisconnectUngraceful = "ungraceful"
+
+// testUngracefulDisconnect connects the hub and kills the network connection without disconnect packet
+// to check the offline detection of the connector
+func (this *Canary) testUngracefulDisconnect(token string, info DeviceInfo, hubId string, broker configuration.ConnectorBroker) {
+	if this.config.ConnectorOfflineDetectionSla == "" {
+		return
+	}
+	time.Sleep(this.getChangeGuaranteeDuration())
+	conn, err := this.connect(token, hubId, broker)
+	if err != nil {
+		return
+	}
+	time.Sleep(this.getChangeGuaranteeDuration())
+	this.checkOfflineDetection(token, info, conn, true)
+}
+
+// checkOfflineDetection closes conn and polls the device until it is reported as offline.
+// the latency is exported and compared to ConnectorOfflineDetectionSla; polling stops after twice the sla.
+// if no sla is configured, conn is only disconnected.
+func (this *Canary) checkOfflineDetection(token string, info DeviceInfo, conn Conn, ungraceful bool) {
+	if this.config.ConnectorOfflineDetectionSla == "" {
+		conn.Disconnect()
+		return
+	}
+	broker := conn.GetBroker()
+	disconnect := DisconnectClean
+	if ungraceful {
+		disconnect = DisconnectUngraceful
+	}
+	labels := append(brokerLabels(broker), disconnect)
+
+	sla, err := time.ParseDuration(this.config.ConnectorOfflineDetectionSla)
+	if err != nil {
+		this.config.GetLogger().Error("invalid connector_offline_detection_sla", "error", err)
+		this.metrics.UncategorizedErr.Inc()
+		conn.Disconnect()
+		return
+	}
+	pollInterval, err := time.ParseDuration(this.config.ConnectorOfflineDetectionPollInterval)
+	if err != nil {
+		this.config.GetLogger().Error("invalid connector_offline_detection_poll_interval", "error", err)
+		this.metrics.UncategorizedErr.Inc()
+		conn.Disconnect()
+		return
+	}
+
+	start := time.Now()
+	if ungraceful {
+		err = conn.Kill()
+		if err != nil {
+			this.config.GetLogger().Warn("unable to kill connection; skip ungraceful offline detection check", "error", err, "broker", broker.Name)
+			conn.Disconnect()
+			return
+		}
+	} else {
+		conn.Disconnect()
+	}
+
+	for {
+		time.Sleep(pollInterval)
+		online, err := this.isDeviceOnline(token, info)
+		latency := time.Since(start)
+		if err == nil && !online {
+			this.metrics.ConnectorOfflineDetectionLatencyMs.WithLabelValues(labels...).Set(float64(latency.Milliseconds()))
+			if latency > sla {
+				this.config.GetLogger().Error("offline detection exceeded sla", "latency", latency.String(), "sla", sla.String(), "disconnect", disconnect, "broker", broker.Name)
+				this.metrics.ConnectorOfflineDetectionSlaErr.WithLabelValues(labels...).Inc()
+			}
+			return
+		}
+		if latency > 2*sla {
+			this.config.GetLogger().Error("device is still online after disconnect", "waited", latency.String(), "sla", sla.String(), "disconnect", disconnect, "broker", broker.Name)
+			this.metrics.ConnectorOfflineDetectionLatencyMs.WithLabelValues(labels...).Set(float64(latency.Milliseconds()))
+			this.metrics.ConnectorOfflineDetectionSlaErr.WithLabelValues(labels...).Inc()
+			return
+		}
+	}
+}
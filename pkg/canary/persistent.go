@@ -0,0 +1,76 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package canary
+
+import (
+	"time"
+
+	"github.com/SENERGY-Platform/canary/pkg/configuration"
+)
+
+// testPersistentBrokerConnections keeps the hub connected to the broker between runs.
+// the persistent connection mode is limited to one broker (see configuration.validatePersistentConnection).
+func (this *Canary) testPersistentBrokerConnections(token string, info DeviceInfo, hubId string, brokers []configuration.ConnectorBroker) {
+	this.testBrokerConnection(this.phaseToken(token), info, hubId, brokers[0], true, true)
+}
+
+// getPersistentConn returns the live connection of the hub.
+// if none exists, a new connection is established.
+func (this *Canary) getPersistentConn(token string, hubId string, broker configuration.ConnectorBroker) (conn Conn, err error) {
+	this.persistentConnMux.Lock()
+	defer this.persistentConnMux.Unlock()
+	if this.persistentConn != nil && this.persistentConn.IsConnected() {
+		return this.persistentConn, nil
+	}
+	if this.persistentConn != nil {
+		this.persistentConn.Disconnect()
+		this.persistentConn = nil
+	}
+	conn, err = this.connect(token, hubId, broker)
+	if err != nil {
+		return conn, err
+	}
+	this.persistentConn = conn
+	return conn, nil
+}
+
+// closePersistentConn disconnects the persistent connection and returns true if one existed
+func (this *Canary) closePersistentConn() bool {
+	this.persistentConnMux.Lock()
+	defer this.persistentConnMux.Unlock()
+	if this.persistentConn == nil {
+		return false
+	}
+	this.persistentConn.Disconnect()
+	this.persistentConn = nil
+	return true
+}
+
+func (this *Canary) onConnectionLost(broker configuration.ConnectorBroker, err error) {
+	this.config.GetLogger().Error("lost connection", "error", err, "broker", broker.Name)
+	this.metrics.ConnectorConnectionLostCount.WithLabelValues(brokerLabels(broker)...).Inc()
+}
+
+func (this *Canary) onReconnecting(broker configuration.ConnectorBroker) {
+	this.config.GetLogger().Info("reconnecting", "broker", broker.Name)
+	this.metrics.ConnectorReconnectCount.WithLabelValues(brokerLabels(broker)...).Inc()
+}
+
+func (this *Canary) onReconnected(broker configuration.ConnectorBroker, lostFor time.Duration) {
+	this.config.GetLogger().Info("reconnected", "lost_for", lostFor.String(), "broker", broker.Name)
+	this.metrics.ConnectorReconnectLatencyMs.WithLabelValues(brokerLabels(broker)...).Set(float64(lostFor.Milliseconds()))
+}
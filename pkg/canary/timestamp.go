@@ -0,0 +1,113 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package canary
+
+import (
+	"encoding/json"
+	"math/rand"
+	"reflect"
+	"time"
+
+	"github.com/SENERGY-Platform/canary/pkg/devicemetadata"
+)
+
+// testDeviceTimestamps publishes a back-dated and a future value with a device-reported time
+// and expects the last-value query to return the device time instead of the ingestion time.
+func (this *Canary) testDeviceTimestamps(token string, info DeviceInfo, conn Conn) {
+	backdate, err := time.ParseDuration(this.config.ConnectorTimestampBackdate)
+	if err != nil {
+		this.metrics.UncategorizedErr.Inc()
+		this.config.GetLogger().Error("invalid connector_timestamp_backdate", "error", err)
+		return
+	}
+	future, err := time.ParseDuration(this.config.ConnectorTimestampFuture)
+	if err != nil {
+		this.metrics.UncategorizedErr.Inc()
+		this.config.GetLogger().Error("invalid connector_timestamp_future", "error", err)
+		return
+	}
+
+	this.metrics.DeviceRepoRequestCount.Inc()
+	start := time.Now()
+	dt, err, _ := this.devicerepo.ReadDeviceType(info.DeviceTypeId, token)
+	this.metrics.DeviceRepoRequestLatencyMs.Set(float64(time.Since(start).Milliseconds()))
+	if err != nil {
+		this.metrics.DeviceRepoRequestErr.Inc()
+		this.config.GetLogger().Error("unable to read device-type", "error", err)
+		return
+	}
+	serviceIds := map[string]string{}
+	for _, s := range dt.Services {
+		serviceIds[s.LocalId] = s.Id
+	}
+
+	now := time.Now().UTC().Truncate(time.Millisecond)
+	checks := []struct {
+		timestamp      string
+		serviceLocalId string
+		time           time.Time
+		value          int
+	}{
+		{timestamp: "past", serviceLocalId: devicemetadata.SensorTimePastServiceLocalId, time: now.Add(-backdate), value: rand.Int()},
+		{timestamp: "future", serviceLocalId: devicemetadata.SensorTimeFutureServiceLocalId, time: now.Add(future), value: rand.Int()},
+	}
+
+	qos := this.config.GetConnectorQosLevels()[0]
+	for _, check := range checks {
+		this.metrics.DeviceTimestampCheckCount.WithLabelValues(check.timestamp).Inc()
+		segmentValue, err := json.Marshal(map[string]interface{}{"value": check.value, "time": check.time.Format(time.RFC3339Nano)})
+		if err != nil {
+			this.metrics.UncategorizedErr.Inc()
+			return
+		}
+		err = this.publishEvent(info, conn, check.serviceLocalId, string(segmentValue), qos)
+		if err != nil {
+			this.metrics.UnexpectedDeviceTimestampErr.WithLabelValues(check.timestamp).Inc()
+			return
+		}
+	}
+
+	time.Sleep(this.getChangeGuaranteeDuration())
+
+	for _, check := range checks {
+		lastValues, err := this.queryLastValues(token, []LastValueRequest{{
+			DeviceId:   info.Id,
+			ServiceId:  serviceIds[check.serviceLocalId],
+			ColumnName: "value.value",
+		}})
+		if err != nil {
+			this.metrics.UnexpectedDeviceTimestampErr.WithLabelValues(check.timestamp).Inc()
+			this.config.GetLogger().Error("unable to read last value", "error", err, "timestamp", check.timestamp)
+			continue
+		}
+		if len(lastValues) != 1 {
+			this.metrics.UnexpectedDeviceTimestampErr.WithLabelValues(check.timestamp).Inc()
+			this.config.GetLogger().Error("unexpected last value list count", "count", len(lastValues), "timestamp", check.timestamp)
+			continue
+		}
+		if !reflect.DeepEqual(lastValues[0].Value, jsonNormalize(check.value)) {
+			this.metrics.UnexpectedDeviceTimestampErr.WithLabelValues(check.timestamp).Inc()
+			this.config.GetLogger().Error("unexpected last value", "expected", check.value, "actual", lastValues[0].Value, "timestamp", check.timestamp)
+			continue
+		}
+		actual, err := time.Parse(time.RFC3339Nano, lastValues[0].Time)
+		if err != nil || !actual.Equal(check.time) {
+			this.metrics.UnexpectedDeviceTimestampErr.WithLabelValues(check.timestamp).Inc()
+			this.config.GetLogger().Error("last value time does not reflect device timestamp", "expected", check.time, "actual", lastValues[0].Time, "timestamp", check.timestamp)
+		}
+	}
+}
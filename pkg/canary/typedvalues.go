@@ -0,0 +1,153 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package canary
+
+import (
+	"encoding/json"
+	"math/rand"
+	"reflect"
+	"runtime/debug"
+	"time"
+
+	"github.com/SENERGY-Platform/canary/pkg/devicemetadata"
+	"github.com/google/uuid"
+)
+
+// typedValue is published to a typed sensor service and expected afterward in the last values of columns
+type typedValue struct {
+	valueType      string
+	serviceLocalId string
+	value          interface{}
+	columns        map[string]interface{}
+}
+
+// newTypedValues returns values for each typed sensor service.
+// the boolean value is passed by the caller, to alternate it between runs.
+func newTypedValues(flag bool) []typedValue {
+	number := rand.Float64() * 1000
+	text := `canary "quoted" \escaped\ ünïcödé ` + uuid.NewString()
+	return []typedValue{
+		{
+			valueType:      "float",
+			serviceLocalId: devicemetadata.SensorFloatServiceLocalId,
+			value:          number,
+			columns:        map[string]interface{}{"value": number},
+		},
+		{
+			valueType:      "string",
+			serviceLocalId: devicemetadata.SensorStringServiceLocalId,
+			value:          text,
+			columns:        map[string]interface{}{"value": text},
+		},
+		{
+			valueType:      "boolean",
+			serviceLocalId: devicemetadata.SensorBoolServiceLocalId,
+			value:          flag,
+			columns:        map[string]interface{}{"value": flag},
+		},
+		{
+			valueType:      "structure",
+			serviceLocalId: devicemetadata.SensorStructServiceLocalId,
+			value: map[string]interface{}{
+				"text": text,
+				"inner": map[string]interface{}{
+					"number": number,
+					"flag":   flag,
+				},
+			},
+			columns: map[string]interface{}{
+				"value.text":         text,
+				"value.inner.number": number,
+				"value.inner.flag":   flag,
+			},
+		},
+	}
+}
+
+// testTypedValues publishes a value to each typed sensor service and checks them with the last-value query
+func (this *Canary) testTypedValues(token string, info DeviceInfo, conn Conn) {
+	this.metrics.DeviceRepoRequestCount.Inc()
+	start := time.Now()
+	dt, err, _ := this.devicerepo.ReadDeviceType(info.DeviceTypeId, token)
+	this.metrics.DeviceRepoRequestLatencyMs.Set(float64(time.Since(start).Milliseconds()))
+	if err != nil {
+		this.metrics.DeviceRepoRequestErr.Inc()
+		this.config.GetLogger().Error("unable to read device-type", "error", err)
+		debug.PrintStack()
+		return
+	}
+	serviceIds := map[string]string{}
+	for _, s := range dt.Services {
+		serviceIds[s.LocalId] = s.Id
+	}
+
+	this.typedValueFlag = !this.typedValueFlag
+	qos := this.config.GetConnectorQosLevels()[0]
+	published := []typedValue{}
+	for _, tv := range newTypedValues(this.typedValueFlag) {
+		this.metrics.TypedDeviceDataCheckCount.WithLabelValues(tv.valueType).Inc()
+		if serviceIds[tv.serviceLocalId] == "" {
+			this.metrics.UnexpectedTypedDeviceDataErr.WithLabelValues(tv.valueType).Inc()
+			this.config.GetLogger().Error("missing typed sensor service in canary device-type", "service", tv.serviceLocalId)
+			continue
+		}
+		segmentValue, err := json.Marshal(tv.value)
+		if err != nil {
+			this.metrics.UncategorizedErr.Inc()
+			continue
+		}
+		err = this.publishEvent(info, conn, tv.serviceLocalId, string(segmentValue), qos)
+		if err != nil {
+			this.metrics.UnexpectedTypedDeviceDataErr.WithLabelValues(tv.valueType).Inc()
+			continue
+		}
+		published = append(published, tv)
+	}
+
+	time.Sleep(this.getChangeGuaranteeDuration())
+
+	for _, tv := range published {
+		body := []LastValueRequest{}
+		expected := []interface{}{}
+		for column, value := range tv.columns {
+			body = append(body, LastValueRequest{
+				DeviceId:   info.Id,
+				ServiceId:  serviceIds[tv.serviceLocalId],
+				ColumnName: column,
+			})
+			expected = append(expected, jsonNormalize(value))
+		}
+		lastValues, err := this.queryLastValues(token, body)
+		if err != nil {
+			this.metrics.UnexpectedTypedDeviceDataErr.WithLabelValues(tv.valueType).Inc()
+			this.config.GetLogger().Error("unable to read last value", "error", err, "value_type", tv.valueType)
+			continue
+		}
+		if len(lastValues) != len(body) {
+			this.metrics.UnexpectedTypedDeviceDataErr.WithLabelValues(tv.valueType).Inc()
+			this.config.GetLogger().Error("unexpected last value list count", "count", len(lastValues), "value_type", tv.valueType)
+			continue
+		}
+		for i, lastValue := range lastValues {
+			if !reflect.DeepEqual(lastValue.Value, expected[i]) {
+				this.metrics.UnexpectedTypedDeviceDataErr.WithLabelValues(tv.valueType).Inc()
+				this.config.GetLogger().Error("unexpected last value", "expected", expected[i], "actual", lastValue.Value, "column", body[i].ColumnName, "value_type", tv.valueType)
+				break
+			}
+		}
+	}
+}
@@ -0,0 +1,164 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package configuration
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+const DefaultConnectorBrokerName = "default"
+
+const TransportTcp = "tcp"
+const TransportWebsocket = "websocket"
+
+const MqttProtocolVersion3 = 3
+const MqttProtocolVersion5 = 5
+
+const DefaultConnectorQos = 2
+
+// ConnectorBroker describes one connector mqtt broker the device connection is checked against
+type ConnectorBroker struct {
+	Name    string `json:"name"`
+	Url     string `json:"url"`
+	UseCert bool   `json:"use_cert"`
+
+	//optional; TransportTcp or TransportWebsocket, defaults to the transport of the url scheme (ws:// and wss:// are websockets)
+	Transport string `json:"transport"`
+	//optional; websocket path, overwrites the path of Url
+	Path string `json:"path"`
+	//optional; additional http headers for the websocket handshake (e.g. Authorization), redacted as a whole
+	Headers map[string]string `json:"headers" config:"secret"`
+
+	//optional; MqttProtocolVersion3 (3.1.1) or MqttProtocolVersion5, defaults to ConnectorMqttProtocolVersion
+	ProtocolVersion int `json:"protocol_version"`
+
+	//optional; defaults to AuthUsername and AuthPassword
+	Username string `json:"username" config:"secret"`
+	Password string `json:"password" config:"secret"`
+}
+
+// GetConnectorBrokers returns the configured connector brokers.
+// if none are configured, ConnectorMqttBrokerUrl and UseCert are used as the only broker.
+func (this Config) GetConnectorBrokers() (result []ConnectorBroker) {
+	if len(this.ConnectorBrokers) == 0 {
+		return []ConnectorBroker{{
+			Name:            DefaultConnectorBrokerName,
+			Url:             this.ConnectorMqttBrokerUrl,
+			UseCert:         this.UseCert,
+			ProtocolVersion: this.ConnectorMqttProtocolVersion,
+			Username:        this.AuthUsername,
+			Password:        this.AuthPassword,
+		}}
+	}
+	for _, broker := range this.ConnectorBrokers {
+		if broker.Name == "" {
+			broker.Name = broker.Url
+		}
+		if broker.ProtocolVersion == 0 {
+			broker.ProtocolVersion = this.ConnectorMqttProtocolVersion
+		}
+		if broker.Username == "" && broker.Password == "" {
+			broker.Username = this.AuthUsername
+			broker.Password = this.AuthPassword
+		}
+		result = append(result, broker)
+	}
+	return result
+}
+
+// GetConnectorQosLevels returns the qos levels used for device events and command responses.
+// if none are configured, DefaultConnectorQos is used.
+func (this Config) GetConnectorQosLevels() (result []byte) {
+	if len(this.ConnectorQosLevels) == 0 {
+		return []byte{DefaultConnectorQos}
+	}
+	for _, qos := range this.ConnectorQosLevels {
+		result = append(result, byte(qos))
+	}
+	return result
+}
+
+// validatePersistentConnection rejects multiple brokers in persistent connection mode:
+// all brokers share the hub id as client id and would replace the persistent connection in every run
+func validatePersistentConnection(config Config) error {
+	if config.ConnectorPersistentConnection && len(config.GetConnectorBrokers()) > 1 {
+		return errors.New("connector_persistent_connection supports only one connector broker")
+	}
+	return nil
+}
+
+func validateConnectorQosLevels(levels []int) error {
+	for _, qos := range levels {
+		if qos < 0 || qos > 2 {
+			return fmt.Errorf("invalid connector qos level: %v", qos)
+		}
+	}
+	return nil
+}
+
+// GetTransport returns TransportWebsocket for websocket brokers and TransportTcp otherwise
+func (this ConnectorBroker) GetTransport() string {
+	if this.Transport != "" {
+		return this.Transport
+	}
+	if strings.HasPrefix(this.Url, "ws://") || strings.HasPrefix(this.Url, "wss://") {
+		return TransportWebsocket
+	}
+	return TransportTcp
+}
+
+// GetProtocolVersion returns MqttProtocolVersion5 for mqtt 5 brokers and MqttProtocolVersion3 otherwise
+func (this ConnectorBroker) GetProtocolVersion() int {
+	if this.ProtocolVersion == MqttProtocolVersion5 {
+		return MqttProtocolVersion5
+	}
+	return MqttProtocolVersion3
+}
+
+// GetUrl returns the broker url matching the transport and path of the broker
+func (this ConnectorBroker) GetUrl() (string, error) {
+	if this.GetTransport() != TransportWebsocket {
+		return this.Url, nil
+	}
+	u, err := url.Parse(this.Url)
+	if err != nil {
+		return "", err
+	}
+	switch u.Scheme {
+	case "tcp", "mqtt":
+		u.Scheme = "ws"
+	case "tls", "ssl", "mqtts", "tcps":
+		u.Scheme = "wss"
+	}
+	if this.Path != "" {
+		u.Path = this.Path
+	}
+	return u.String(), nil
+}
+
+// GetHeaders returns Headers as http.Header
+func (this ConnectorBroker) GetHeaders() http.Header {
+	result := http.Header{}
+	for key, value := range this.Headers {
+		result.Set(key, value)
+	}
+	return result
+}
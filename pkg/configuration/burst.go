@@ -0,0 +1,40 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package configuration
+
+import (
+	"errors"
+	"time"
+)
+
+func validateConnectorBurst(config Config) error {
+	if !config.ConnectorBurstCheck {
+		return nil
+	}
+	if config.ConnectorBurstSize <= 0 || config.ConnectorBurstRate <= 0 {
+		return errors.New("connector_burst_check needs a positive connector_burst_size and connector_burst_rate")
+	}
+	if config.GetConnectorBurstInterval() <= 0 {
+		return errors.New("connector_burst_rate is too high for a publish interval of at least 1ns")
+	}
+	return nil
+}
+
+// GetConnectorBurstInterval returns the time between two publishes of the burst check
+func (this Config) GetConnectorBurstInterval() time.Duration {
+	return time.Duration(float64(time.Second) / this.ConnectorBurstRate)
+}
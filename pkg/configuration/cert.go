@@ -0,0 +1,42 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package configuration
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// validateCertRenewBefore requires a cert_renew_before shorter than cert_exp_time; otherwise every new cert would be due for renewal
+func validateCertRenewBefore(config Config) error {
+	if config.CertRenewBefore == "" {
+		return nil
+	}
+	renewBefore, err := time.ParseDuration(config.CertRenewBefore)
+	if err != nil {
+		return fmt.Errorf("invalid cert_renew_before: %w", err)
+	}
+	exp, err := time.ParseDuration(config.CertExpTime)
+	if err != nil {
+		return fmt.Errorf("invalid cert_exp_time: %w", err)
+	}
+	if renewBefore >= exp {
+		return errors.New("cert_renew_before must be shorter than cert_exp_time")
+	}
+	return nil
+}
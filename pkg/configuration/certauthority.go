@@ -0,0 +1,33 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package configuration
+
+import (
+	"fmt"
+	"time"
+)
+
+func validateCertAuthorityCheck(config Config) error {
+	if !config.CertAuthorityCheck {
+		return nil
+	}
+	_, err := time.ParseDuration(config.CertAuthorityCheckExpTime)
+	if err != nil {
+		return fmt.Errorf("invalid cert_authority_check_exp_time: %w", err)
+	}
+	return nil
+}
@@ -36,18 +36,73 @@ type Config struct {
 
 	GuaranteeChangeAfter string `json:"guarantee_change_after"`
 
-	AuthEndpoint string `json:"auth_endpoint"`
-	AuthClientId string `json:"auth_client_id" config:"secret"`
-	AuthUsername string `json:"auth_username" config:"secret"`
-	AuthPassword string `json:"auth_password" config:"secret"`
+	AuthEndpoint     string `json:"auth_endpoint"`
+	AuthRealm        string `json:"auth_realm"`
+	AuthTokenPath    string `json:"auth_token_path"`
+	AuthLogoutPath   string `json:"auth_logout_path"`
+	AuthUseDiscovery bool   `json:"auth_use_discovery"`
+	AuthGrantType    string `json:"auth_grant_type"`
+	AuthClientId     string `json:"auth_client_id" config:"secret"`
+	AuthClientSecret string `json:"auth_client_secret" config:"secret"`
+	AuthUsername     string `json:"auth_username" config:"secret"`
+	AuthPassword     string `json:"auth_password" config:"secret"`
+
+	AuthExpectedRoles  []string          `json:"auth_expected_roles"`
+	AuthExpectedClaims map[string]string `json:"auth_expected_claims"`
 
 	DeviceManagerUrl        string `json:"device_manager_url"`
 	DeviceRepositoryUrl     string `json:"device_repository_url"`
 	ConnectorMqttBrokerUrl  string `json:"connector_mqtt_broker_url"`
 	LastValueQueryUrl       string `json:"last_value_query_url"`
+	HistoricQueryUrl        string `json:"historic_query_url"`
 	NotificationUrl         string `json:"notification_url"`
 	ProcessDeploymentUrl    string `json:"process_deployment_url"`
 	ProcessEngineWrapperUrl string `json:"process_engine_wrapper_url"`
+	DeviceCommandUrl        string `json:"device_command_url"`
+
+	ConnectorBrokers              []ConnectorBroker `json:"connector_brokers"`
+	ConnectorMqttProtocolVersion  int               `json:"connector_mqtt_protocol_version"`
+	ConnectorPersistentConnection bool              `json:"connector_persistent_connection"`
+	ConnectorQosLevels            []int             `json:"connector_qos_levels"`
+
+	ConnectorOfflineDetectionSla          string `json:"connector_offline_detection_sla"`
+	ConnectorOfflineDetectionPollInterval string `json:"connector_offline_detection_poll_interval"`
+
+	ConnectorAclCheck                bool   `json:"connector_acl_check"`
+	ConnectorAclForeignOwnerId       string `json:"connector_acl_foreign_owner_id"`
+	ConnectorAclForeignDeviceLocalId string `json:"connector_acl_foreign_device_local_id"`
+
+	ConnectorDeviceErrorCheck bool `json:"connector_device_error_check"`
+	ConnectorTypedValueCheck  bool `json:"connector_typed_value_check"`
+
+	ConnectorConversionCheck                  bool    `json:"connector_conversion_check"`
+	ConnectorConversionTargetCharacteristicId string  `json:"connector_conversion_target_characteristic_id"`
+	ConnectorConversionScale                  float64 `json:"connector_conversion_scale"`
+	ConnectorConversionOffset                 float64 `json:"connector_conversion_offset"`
+	ConnectorConversionTolerance              float64 `json:"connector_conversion_tolerance"`
+
+	ConnectorTimestampCheck    bool   `json:"connector_timestamp_check"`
+	ConnectorTimestampBackdate string `json:"connector_timestamp_backdate"`
+	ConnectorTimestampFuture   string `json:"connector_timestamp_future"`
+
+	ConnectorBurstCheck bool    `json:"connector_burst_check"`
+	ConnectorBurstSize  int     `json:"connector_burst_size"`
+	ConnectorBurstRate  float64 `json:"connector_burst_rate"`
+
+	ConnectorHistoricCheck bool `json:"connector_historic_check"`
+
+	DeviceCommandCheck bool `json:"device_command_check"`
+
+	ProcessCommandScenarios []string `json:"process_command_scenarios"`
+	ProcessIncidentTimeout  string   `json:"process_incident_timeout"`
+
+	ProcessResultVariableName string `json:"process_result_variable_name"`
+
+	DeviceSimulatorResponseValues          []int   `json:"device_simulator_response_values"`
+	DeviceSimulatorResponseDelay           string  `json:"device_simulator_response_delay"`
+	DeviceSimulatorResponseDropProbability float64 `json:"device_simulator_response_drop_probability"`
+	DeviceSimulatorEventPattern            string  `json:"device_simulator_event_pattern"`
+	DeviceSimulatorEventConstantValue      int     `json:"device_simulator_event_constant_value"`
 
 	CanaryDeviceClassId          string `json:"canary_device_class_id"`
 	CanaryCmdFunctionId          string `json:"canary_cmd_function_id"`
@@ -70,6 +125,18 @@ type Config struct {
 	CertKeyFilePath  string `json:"cert_key_file_path"`
 	CertFilePath     string `json:"cert_file_path"`
 	CertExpTime      string `json:"cert_exp_time"`
+	CertRenewBefore  string `json:"cert_renew_before"`
+
+	CertAuthorityCertFilePath string `json:"cert_authority_cert_file_path"`
+
+	CertAuthorityCheck        bool   `json:"cert_authority_check"`
+	CertAuthorityCheckExpTime string `json:"cert_authority_check_exp_time"`
+
+	EnvironmentName string        `json:"environment_name"`
+	Environments    []Environment `json:"environments"`
+
+	IdentityName string     `json:"identity_name"`
+	Identities   []Identity `json:"identities"`
 
 	LogLevel string       `json:"log_level"`
 	logger   *slog.Logger `json:"-"`
@@ -85,7 +152,37 @@ func Load(location string) (config Config, err error) {
 	if err != nil {
 		return config, err
 	}
-	handleEnvironmentVars(&config)
+	used, err := handleEnvironmentVars(&config)
+	if err != nil {
+		return config, err
+	}
+	for _, fieldName := range used {
+		config.GetLogger().Info("use environment variable", "name", fieldNameToEnvName(fieldName), "value", config.redactedField(fieldName))
+	}
+	err = validateEnvironments(config)
+	if err != nil {
+		return config, err
+	}
+	err = validateEnvironmentConfigs(config)
+	if err != nil {
+		return config, err
+	}
+	err = validateConnectorQosLevels(config.ConnectorQosLevels)
+	if err != nil {
+		return config, err
+	}
+	err = validateProcessCommandScenarios(config.ProcessCommandScenarios)
+	if err != nil {
+		return config, err
+	}
+	err = validateProcessIncidentTimeout(config)
+	if err != nil {
+		return config, err
+	}
+	err = validateDeviceSimulator(config)
+	if err != nil {
+		return config, err
+	}
 	return config, nil
 }
 
@@ -105,18 +202,16 @@ func fieldNameToEnvName(s string) string {
 }
 
 // preparations for docker
-func handleEnvironmentVars(config *Config) {
+// returns the names of the fields set by environment variables
+func handleEnvironmentVars(config *Config) (used []string, err error) {
 	configValue := reflect.Indirect(reflect.ValueOf(config))
 	configType := configValue.Type()
 	for index := 0; index < configType.NumField(); index++ {
 		fieldName := configType.Field(index).Name
-		fieldConfig := configType.Field(index).Tag.Get("config")
 		envName := fieldNameToEnvName(fieldName)
 		envValue := os.Getenv(envName)
 		if envValue != "" {
-			if !strings.Contains(fieldConfig, "secret") {
-				fmt.Println("use environment variable: ", envName, " = ", envValue)
-			}
+			used = append(used, fieldName)
 			if configValue.FieldByName(fieldName).Kind() == reflect.Int64 || configValue.FieldByName(fieldName).Kind() == reflect.Int {
 				i, _ := strconv.ParseInt(envValue, 10, 64)
 				configValue.FieldByName(fieldName).SetInt(i)
@@ -132,7 +227,13 @@ func handleEnvironmentVars(config *Config) {
 				f, _ := strconv.ParseFloat(envValue, 64)
 				configValue.FieldByName(fieldName).SetFloat(f)
 			}
-			if configValue.FieldByName(fieldName).Kind() == reflect.Slice {
+			if configValue.FieldByName(fieldName).Kind() == reflect.Slice && configValue.FieldByName(fieldName).Type().Elem().Kind() != reflect.String {
+				//lists of structs are expected as json
+				err = json.Unmarshal([]byte(envValue), configValue.FieldByName(fieldName).Addr().Interface())
+				if err != nil {
+					return used, fmt.Errorf("unable to parse json in environment variable %v: %w", envName, err)
+				}
+			} else if configValue.FieldByName(fieldName).Kind() == reflect.Slice {
 				val := []string{}
 				for _, element := range strings.Split(envValue, ",") {
 					val = append(val, strings.TrimSpace(element))
@@ -151,6 +252,7 @@ func handleEnvironmentVars(config *Config) {
 			}
 		}
 	}
+	return used, nil
 }
 
 func (this *Config) GetLogger() *slog.Logger {
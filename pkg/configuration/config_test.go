@@ -0,0 +1,282 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package configuration
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestRedacted(t *testing.T) {
+	t.Setenv("CONNECTOR_MQTT_BROKER_URL", "tcp://env-broker:1883")
+	t.Setenv("AUTH_PASSWORD", "env-password")
+
+	config, err := Load("./../../config.json")
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if config.AuthPassword != "env-password" {
+		t.Error("environment variable not applied", config.AuthPassword)
+		return
+	}
+
+	redacted := config.Redacted()
+	if redacted["connector_mqtt_broker_url"] != "tcp://env-broker:1883" {
+		t.Error("unexpected broker url", redacted["connector_mqtt_broker_url"])
+	}
+	if redacted["auth_password"] != RedactedValue {
+		t.Error("password not redacted", redacted["auth_password"])
+	}
+	if redacted["auth_username"] != "" {
+		t.Error("empty secret should stay empty", redacted["auth_username"])
+	}
+
+	b, err := json.Marshal(redacted)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if strings.Contains(string(b), "env-password") {
+		t.Error("secret leaked", string(b))
+	}
+}
+
+func TestRedactedBrokerHeaders(t *testing.T) {
+	config := Config{ConnectorBrokers: []ConnectorBroker{{
+		Name:    "ws",
+		Url:     "wss://broker:443/mqtt",
+		Headers: map[string]string{"Authorization": "Bearer header-token"},
+	}}}
+	b, err := json.Marshal(config.Redacted())
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if strings.Contains(string(b), "header-token") {
+		t.Error("header leaked", string(b))
+	}
+	brokers, _ := config.Redacted()["connector_brokers"].([]interface{})
+	if len(brokers) != 1 || brokers[0].(map[string]interface{})["headers"] != RedactedValue {
+		t.Error("headers not redacted", brokers)
+	}
+}
+
+func TestGetEnvironments(t *testing.T) {
+	useCert := false
+	config := Config{
+		EnvironmentName:        "default",
+		AuthEndpoint:           "https://auth.prod",
+		ConnectorMqttBrokerUrl: "tls://connector.prod:8883",
+		UseCert:                true,
+		CertFilePath:           "./cert.pem",
+		CertKeyFilePath:        "./key.pem",
+		Environments: []Environment{
+			{Name: "prod"},
+			{Name: "dev", AuthEndpoint: "https://auth.dev", UseCert: &useCert, CertFilePath: "/certs/dev.pem"},
+		},
+	}
+
+	envs := config.GetEnvironments()
+	if len(envs) != 2 {
+		t.Error("unexpected environment count", len(envs))
+		return
+	}
+	prod, dev := envs[0], envs[1]
+	if prod.EnvironmentName != "prod" || prod.AuthEndpoint != "https://auth.prod" || !prod.UseCert || prod.CertFilePath != "prod_cert.pem" || prod.CertKeyFilePath != "prod_key.pem" {
+		t.Errorf("unexpected prod config: %#v", prod)
+	}
+	if dev.EnvironmentName != "dev" || dev.AuthEndpoint != "https://auth.dev" || dev.UseCert || dev.CertFilePath != "/certs/dev.pem" || dev.ConnectorMqttBrokerUrl != "tls://connector.prod:8883" {
+		t.Errorf("unexpected dev config: %#v", dev)
+	}
+	if len(dev.Environments) != 0 {
+		t.Error("environment config should not contain environments")
+	}
+
+	config.Environments = nil
+	envs = config.GetEnvironments()
+	if len(envs) != 1 || envs[0].EnvironmentName != "default" || envs[0].CertFilePath != "./cert.pem" {
+		t.Errorf("unexpected default environment: %#v", envs)
+	}
+}
+
+func TestGetIdentities(t *testing.T) {
+	config := Config{
+		AuthUsername:  "canary",
+		AuthPassword:  "canary-pw",
+		CanaryHubName: "canary",
+		CertFilePath:  "./cert.pem",
+		Environments: []Environment{
+			{Name: "prod"},
+			{Name: "dev", Identities: []Identity{
+				{Name: "admin", AuthUsername: "admin", AuthPassword: "admin-pw"},
+				{Name: "group", AuthUsername: "group-user", AuthPassword: "group-pw", CanaryHubName: "group-canary"},
+			}},
+		},
+	}
+	envs := config.GetEnvironments()
+
+	prod := envs[0].GetIdentities()
+	if len(prod) != 1 || prod[0].IdentityName != DefaultIdentityName || prod[0].AuthUsername != "canary" || prod[0].CertFilePath != "prod_cert.pem" {
+		t.Errorf("unexpected prod identities: %#v", prod)
+	}
+
+	dev := envs[1].GetIdentities()
+	if len(dev) != 2 {
+		t.Error("unexpected dev identity count", len(dev))
+		return
+	}
+	if dev[0].IdentityName != "admin" || dev[0].AuthUsername != "admin" || dev[0].CanaryHubName != "canary" || dev[0].CertFilePath != "admin_dev_cert.pem" {
+		t.Errorf("unexpected admin identity: %#v", dev[0])
+	}
+	if dev[1].IdentityName != "group" || dev[1].AuthPassword != "group-pw" || dev[1].CanaryHubName != "group-canary" || dev[1].EnvironmentName != "dev" {
+		t.Errorf("unexpected group identity: %#v", dev[1])
+	}
+
+	config.Environments[1].Identities = append(config.Environments[1].Identities, Identity{Name: "admin"})
+	if validateEnvironments(config) == nil {
+		t.Error("expected duplicate identity error")
+	}
+}
+
+func TestConnectorBrokerUrl(t *testing.T) {
+	cases := []struct {
+		broker    ConnectorBroker
+		transport string
+		url       string
+	}{
+		{ConnectorBroker{Url: "tls://connector:8883"}, TransportTcp, "tls://connector:8883"},
+		{ConnectorBroker{Url: "wss://connector:443/mqtt"}, TransportWebsocket, "wss://connector:443/mqtt"},
+		{ConnectorBroker{Url: "wss://connector:443/mqtt", Path: "/ws"}, TransportWebsocket, "wss://connector:443/ws"},
+		{ConnectorBroker{Url: "tls://connector:443", Transport: TransportWebsocket, Path: "/mqtt"}, TransportWebsocket, "wss://connector:443/mqtt"},
+		{ConnectorBroker{Url: "tcp://connector:80", Transport: TransportWebsocket}, TransportWebsocket, "ws://connector:80"},
+	}
+	for _, c := range cases {
+		if transport := c.broker.GetTransport(); transport != c.transport {
+			t.Error("unexpected transport", c.broker.Url, transport)
+		}
+		u, err := c.broker.GetUrl()
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+		if u != c.url {
+			t.Error("unexpected url", c.url, u)
+		}
+	}
+}
+
+func TestConnectorQosLevels(t *testing.T) {
+	if levels := (Config{}).GetConnectorQosLevels(); len(levels) != 1 || levels[0] != DefaultConnectorQos {
+		t.Error("unexpected default qos levels", levels)
+	}
+	if levels := (Config{ConnectorQosLevels: []int{0, 1}}).GetConnectorQosLevels(); len(levels) != 2 || levels[0] != 0 || levels[1] != 1 {
+		t.Error("unexpected qos levels", levels)
+	}
+	if validateConnectorQosLevels([]int{0, 3}) == nil {
+		t.Error("expected invalid qos level error")
+	}
+}
+
+func TestProcessIncidentTimeout(t *testing.T) {
+	if err := validateProcessIncidentTimeout(Config{}); err != nil {
+		t.Error(err)
+	}
+	if validateProcessIncidentTimeout(Config{ProcessCommandScenarios: []string{CommandScenarioSuccess, CommandScenarioError}}) == nil {
+		t.Error("expected missing timeout error")
+	}
+	if err := validateProcessIncidentTimeout(Config{ProcessCommandScenarios: []string{CommandScenarioNoResponse}, ProcessIncidentTimeout: "3m"}); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestValidateEnvironmentConfigs(t *testing.T) {
+	enabled := true
+	config := Config{
+		ConnectorBrokers: []ConnectorBroker{{Name: "a", Url: "tcp://a:1883"}, {Name: "b", Url: "tcp://b:1883"}},
+		Environments:     []Environment{{Name: "dev"}},
+	}
+	if err := validateEnvironmentConfigs(config); err != nil {
+		t.Error(err)
+	}
+	config.Environments = append(config.Environments, Environment{Name: "prod", ConnectorPersistentConnection: &enabled})
+	if validateEnvironmentConfigs(config) == nil {
+		t.Error("expected error for persistent connection with multiple brokers in environment")
+	}
+
+	config = Config{Environments: []Environment{{Name: "dev"}, {Name: "prod", ConnectorConversionCheck: &enabled}}}
+	if validateEnvironmentConfigs(config) == nil {
+		t.Error("expected error for conversion check without target characteristic in environment")
+	}
+
+	config = Config{Environments: []Environment{{Name: "dev"}, {Name: "prod", ConnectorTimestampCheck: &enabled}}}
+	if validateEnvironmentConfigs(config) == nil {
+		t.Error("expected error for timestamp check without durations in environment")
+	}
+
+	config = Config{ConnectorBurstSize: 10, ConnectorBurstRate: 2e9, Environments: []Environment{{Name: "dev"}, {Name: "prod", ConnectorBurstCheck: &enabled}}}
+	if validateEnvironmentConfigs(config) == nil {
+		t.Error("expected error for burst check without positive interval in environment")
+	}
+
+	config = Config{Environments: []Environment{{Name: "dev"}, {Name: "prod", CertAuthorityCheck: &enabled}}}
+	if validateEnvironmentConfigs(config) == nil {
+		t.Error("expected error for cert authority check without exp time in environment")
+	}
+}
+
+func TestConnectorOfflineDetection(t *testing.T) {
+	config := Config{ConnectorOfflineDetectionSla: "", ConnectorOfflineDetectionPollInterval: ""}
+	if err := validateConnectorOfflineDetection(config); err != nil {
+		t.Error(err)
+	}
+	config = Config{ConnectorOfflineDetectionSla: "30s", ConnectorOfflineDetectionPollInterval: "1s"}
+	if err := validateConnectorOfflineDetection(config); err != nil {
+		t.Error(err)
+	}
+	for _, invalid := range []Config{
+		{ConnectorOfflineDetectionSla: "foo", ConnectorOfflineDetectionPollInterval: "1s"},
+		{ConnectorOfflineDetectionSla: "-1s", ConnectorOfflineDetectionPollInterval: "1s"},
+		{ConnectorOfflineDetectionSla: "30s", ConnectorOfflineDetectionPollInterval: ""},
+		{ConnectorOfflineDetectionSla: "30s", ConnectorOfflineDetectionPollInterval: "0s"},
+	} {
+		if validateConnectorOfflineDetection(invalid) == nil {
+			t.Errorf("expected error for %v/%v", invalid.ConnectorOfflineDetectionSla, invalid.ConnectorOfflineDetectionPollInterval)
+		}
+	}
+}
+
+func TestCertRenewBefore(t *testing.T) {
+	for _, valid := range []Config{
+		{CertRenewBefore: "", CertExpTime: ""},
+		{CertRenewBefore: "720h", CertExpTime: "8760h"},
+	} {
+		if err := validateCertRenewBefore(valid); err != nil {
+			t.Error(err)
+		}
+	}
+	for _, invalid := range []Config{
+		{CertRenewBefore: "foo", CertExpTime: "8760h"},
+		{CertRenewBefore: "720h", CertExpTime: ""},
+		{CertRenewBefore: "720h", CertExpTime: "720h"},
+	} {
+		if validateCertRenewBefore(invalid) == nil {
+			t.Errorf("expected error for %v/%v", invalid.CertRenewBefore, invalid.CertExpTime)
+		}
+	}
+}
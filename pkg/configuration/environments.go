@@ -0,0 +1,274 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package configuration
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"time"
+)
+
+const DefaultEnvironmentName = "default"
+const DefaultIdentityName = "default"
+
+// Environment describes one monitored platform.
+// every non-empty field overwrites the Config field with the same name.
+type Environment struct {
+	Name string `json:"name"`
+
+	AuthEndpoint     string `json:"auth_endpoint"`
+	AuthRealm        string `json:"auth_realm"`
+	AuthTokenPath    string `json:"auth_token_path"`
+	AuthLogoutPath   string `json:"auth_logout_path"`
+	AuthUseDiscovery *bool  `json:"auth_use_discovery"`
+	AuthGrantType    string `json:"auth_grant_type"`
+	AuthClientId     string `json:"auth_client_id" config:"secret"`
+	AuthClientSecret string `json:"auth_client_secret" config:"secret"`
+	AuthUsername     string `json:"auth_username" config:"secret"`
+	AuthPassword     string `json:"auth_password" config:"secret"`
+
+	DeviceManagerUrl        string `json:"device_manager_url"`
+	DeviceRepositoryUrl     string `json:"device_repository_url"`
+	ConnectorMqttBrokerUrl  string `json:"connector_mqtt_broker_url"`
+	LastValueQueryUrl       string `json:"last_value_query_url"`
+	HistoricQueryUrl        string `json:"historic_query_url"`
+	NotificationUrl         string `json:"notification_url"`
+	ProcessDeploymentUrl    string `json:"process_deployment_url"`
+	ProcessEngineWrapperUrl string `json:"process_engine_wrapper_url"`
+	ProcessIncidentTimeout  string `json:"process_incident_timeout"`
+	DeviceCommandUrl        string `json:"device_command_url"`
+
+	ConnectorBrokers              []ConnectorBroker `json:"connector_brokers"`
+	ConnectorMqttProtocolVersion  int               `json:"connector_mqtt_protocol_version"`
+	ConnectorPersistentConnection *bool             `json:"connector_persistent_connection"`
+	ConnectorOfflineDetectionSla  string            `json:"connector_offline_detection_sla"`
+
+	ConnectorAclCheck                *bool  `json:"connector_acl_check"`
+	ConnectorAclForeignOwnerId       string `json:"connector_acl_foreign_owner_id"`
+	ConnectorAclForeignDeviceLocalId string `json:"connector_acl_foreign_device_local_id"`
+
+	ConnectorDeviceErrorCheck *bool `json:"connector_device_error_check"`
+	ConnectorTypedValueCheck  *bool `json:"connector_typed_value_check"`
+	ConnectorConversionCheck  *bool `json:"connector_conversion_check"`
+	ConnectorTimestampCheck   *bool `json:"connector_timestamp_check"`
+	ConnectorBurstCheck       *bool `json:"connector_burst_check"`
+	ConnectorHistoricCheck    *bool `json:"connector_historic_check"`
+	DeviceCommandCheck        *bool `json:"device_command_check"`
+
+	CanaryHubName string `json:"canary_hub_name"`
+
+	TopicsWithOwner *bool `json:"topics_with_owner"`
+
+	UseCert          *bool  `json:"use_cert"`
+	CertAuthorityUrl string `json:"cert_authority_url"`
+	CertKeyFilePath  string `json:"cert_key_file_path"`
+	CertFilePath     string `json:"cert_file_path"`
+
+	CertAuthorityCertFilePath string `json:"cert_authority_cert_file_path"`
+	CertAuthorityCheck        *bool  `json:"cert_authority_check"`
+
+	Identities []Identity `json:"identities"`
+}
+
+// Identity describes one canary user.
+// every non-empty field overwrites the Config field with the same name.
+type Identity struct {
+	Name string `json:"name"`
+
+	AuthGrantType    string `json:"auth_grant_type"`
+	AuthClientId     string `json:"auth_client_id" config:"secret"`
+	AuthClientSecret string `json:"auth_client_secret" config:"secret"`
+	AuthUsername     string `json:"auth_username" config:"secret"`
+	AuthPassword     string `json:"auth_password" config:"secret"`
+
+	CanaryHubName string `json:"canary_hub_name"`
+
+	CertKeyFilePath string `json:"cert_key_file_path"`
+	CertFilePath    string `json:"cert_file_path"`
+}
+
+// GetEnvironments returns one config per configured environment.
+// if no environments are configured, the config itself is used as the only environment.
+// the loggers of the returned configs add the environment name to every log record.
+func (this *Config) GetEnvironments() (result []Config) {
+	if len(this.Environments) == 0 {
+		return []Config{this.forEnvironment(Environment{Name: this.EnvironmentName}, false)}
+	}
+	for _, env := range this.Environments {
+		result = append(result, this.forEnvironment(env, true))
+	}
+	return result
+}
+
+func (this *Config) forEnvironment(env Environment, separateCertFiles bool) (result Config) {
+	if env.Name == "" {
+		env.Name = DefaultEnvironmentName
+	}
+	result = *this
+	result.Environments = nil
+	result.EnvironmentName = env.Name
+
+	//environments share the base config; prevent them from overwriting each others cert files
+	if separateCertFiles {
+		if env.CertKeyFilePath == "" {
+			env.CertKeyFilePath = withNamePrefix(this.CertKeyFilePath, env.Name)
+		}
+		if env.CertFilePath == "" {
+			env.CertFilePath = withNamePrefix(this.CertFilePath, env.Name)
+		}
+	}
+
+	overwriteFields(&result, env)
+
+	result.logger = this.GetLogger().With("environment", env.Name)
+	return result
+}
+
+// GetIdentities returns one config per configured identity.
+// if no identities are configured, the config itself is used as the only identity.
+// the loggers of the returned configs add the identity name to every log record.
+func (this *Config) GetIdentities() (result []Config) {
+	if len(this.Identities) == 0 {
+		return []Config{this.forIdentity(Identity{Name: this.IdentityName}, false)}
+	}
+	for _, identity := range this.Identities {
+		result = append(result, this.forIdentity(identity, true))
+	}
+	return result
+}
+
+func (this *Config) forIdentity(identity Identity, separateCertFiles bool) (result Config) {
+	if identity.Name == "" {
+		identity.Name = DefaultIdentityName
+	}
+	result = *this
+	result.Identities = nil
+	result.IdentityName = identity.Name
+
+	//every identity owns its own hub and needs its own cert
+	if separateCertFiles {
+		if identity.CertKeyFilePath == "" {
+			identity.CertKeyFilePath = withNamePrefix(this.CertKeyFilePath, identity.Name)
+		}
+		if identity.CertFilePath == "" {
+			identity.CertFilePath = withNamePrefix(this.CertFilePath, identity.Name)
+		}
+	}
+
+	overwriteFields(&result, identity)
+
+	result.logger = this.GetLogger().With("identity", identity.Name)
+	return result
+}
+
+// overwriteFields sets every non-empty field of overwrites to the config field with the same name
+func overwriteFields(config *Config, overwrites interface{}) {
+	configValue := reflect.ValueOf(config).Elem()
+	overwritesValue := reflect.ValueOf(overwrites)
+	for i := 0; i < overwritesValue.NumField(); i++ {
+		field := overwritesValue.Type().Field(i)
+		value := overwritesValue.Field(i)
+		if field.Name == "Name" || value.IsZero() {
+			continue
+		}
+		if value.Kind() == reflect.Pointer {
+			value = value.Elem()
+		}
+		target := configValue.FieldByName(field.Name)
+		if target.IsValid() && target.Type() == value.Type() {
+			target.Set(value)
+		}
+	}
+}
+
+func withNamePrefix(path string, name string) string {
+	if path == "" {
+		return path
+	}
+	dir, file := filepath.Split(path)
+	return filepath.Join(dir, name+"_"+file)
+}
+
+func validateEnvironments(config Config) error {
+	err := validateIdentities(config.Identities)
+	if err != nil {
+		return err
+	}
+	names := map[string]bool{}
+	for _, env := range config.Environments {
+		name := env.Name
+		if name == "" {
+			name = DefaultEnvironmentName
+		}
+		if names[name] {
+			return errors.New("duplicate environment name: " + name)
+		}
+		names[name] = true
+		err = validateIdentities(env.Identities)
+		if err != nil {
+			return fmt.Errorf("environment %v: %w", name, err)
+		}
+		if env.ProcessIncidentTimeout != "" {
+			_, err = time.ParseDuration(env.ProcessIncidentTimeout)
+			if err != nil {
+				return fmt.Errorf("environment %v: invalid process_incident_timeout: %w", name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// environmentValidators check settings that environments may overwrite (e.g. by enabling a check)
+var environmentValidators = []func(config Config) error{
+	validatePersistentConnection,
+	validateConnectorOfflineDetection,
+	validateConnectorConversion,
+	validateConnectorTimestamp,
+	validateConnectorBurst,
+	validateCertRenewBefore,
+	validateCertAuthorityCheck,
+}
+
+// validateEnvironmentConfigs runs the environmentValidators for the config of every environment
+func validateEnvironmentConfigs(config Config) error {
+	for _, env := range config.GetEnvironments() {
+		for _, validate := range environmentValidators {
+			err := validate(env)
+			if err != nil {
+				return fmt.Errorf("environment %v: %w", env.EnvironmentName, err)
+			}
+		}
+	}
+	return nil
+}
+
+func validateIdentities(identities []Identity) error {
+	names := map[string]bool{}
+	for _, identity := range identities {
+		name := identity.Name
+		if name == "" {
+			name = DefaultIdentityName
+		}
+		if names[name] {
+			return errors.New("duplicate identity name: " + name)
+		}
+		names[name] = true
+	}
+	return nil
+}
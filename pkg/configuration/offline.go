@@ -0,0 +1,45 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package configuration
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// validateConnectorOfflineDetection requires positive durations if the offline detection is enabled by a non-empty sla
+func validateConnectorOfflineDetection(config Config) error {
+	if config.ConnectorOfflineDetectionSla == "" {
+		return nil
+	}
+	sla, err := time.ParseDuration(config.ConnectorOfflineDetectionSla)
+	if err != nil {
+		return fmt.Errorf("invalid connector_offline_detection_sla: %w", err)
+	}
+	if sla <= 0 {
+		return errors.New("connector_offline_detection_sla must be positive")
+	}
+	pollInterval, err := time.ParseDuration(config.ConnectorOfflineDetectionPollInterval)
+	if err != nil {
+		return fmt.Errorf("invalid connector_offline_detection_poll_interval: %w", err)
+	}
+	if pollInterval <= 0 {
+		return errors.New("connector_offline_detection_poll_interval must be positive")
+	}
+	return nil
+}
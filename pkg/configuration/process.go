@@ -0,0 +1,76 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package configuration
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+)
+
+// the canary device answers process commands with a response
+const CommandScenarioSuccess = "success"
+
+// the canary device answers process commands with a command error
+const CommandScenarioError = "error"
+
+// the canary device does not answer process commands
+const CommandScenarioNoResponse = "no_response"
+
+// GetProcessCommandScenarios returns the scenarios used in turns by the process check.
+// if none are configured, CommandScenarioSuccess is used.
+func (this Config) GetProcessCommandScenarios() []string {
+	if len(this.ProcessCommandScenarios) == 0 {
+		return []string{CommandScenarioSuccess}
+	}
+	return this.ProcessCommandScenarios
+}
+
+// DefaultProcessResultVariableName is the variable the command worker stores the command result in
+const DefaultProcessResultVariableName = "outputs"
+
+// GetProcessResultVariableName returns the name of the process variable holding the command result
+func (this Config) GetProcessResultVariableName() string {
+	if this.ProcessResultVariableName == "" {
+		return DefaultProcessResultVariableName
+	}
+	return this.ProcessResultVariableName
+}
+
+func validateProcessCommandScenarios(scenarios []string) error {
+	for _, scenario := range scenarios {
+		if !slices.Contains([]string{CommandScenarioSuccess, CommandScenarioError, CommandScenarioNoResponse}, scenario) {
+			return errors.New("invalid process command scenario: " + scenario)
+		}
+	}
+	return nil
+}
+
+// validateProcessIncidentTimeout checks the timeout if a scenario expects an incident
+func validateProcessIncidentTimeout(config Config) error {
+	if !slices.ContainsFunc(config.GetProcessCommandScenarios(), func(scenario string) bool {
+		return scenario != CommandScenarioSuccess
+	}) {
+		return nil
+	}
+	_, err := time.ParseDuration(config.ProcessIncidentTimeout)
+	if err != nil {
+		return fmt.Errorf("invalid process_incident_timeout: %w", err)
+	}
+	return nil
+}
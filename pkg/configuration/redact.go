@@ -0,0 +1,110 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package configuration
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+const RedactedValue = "***"
+
+// Redacted returns the config as generic json structure.
+// every non-empty field tagged with `config:"secret"` is replaced by RedactedValue
+func (this Config) Redacted() map[string]interface{} {
+	result, _ := redact(reflect.ValueOf(this)).(map[string]interface{})
+	return result
+}
+
+// redactedField returns the redacted value of the top level config field with the given go name
+func (this Config) redactedField(fieldName string) interface{} {
+	field, ok := reflect.TypeOf(this).FieldByName(fieldName)
+	if !ok {
+		return nil
+	}
+	value := reflect.ValueOf(this).FieldByIndex(field.Index)
+	if isSecret(field) && !value.IsZero() {
+		return RedactedValue
+	}
+	return redact(value)
+}
+
+func isSecret(field reflect.StructField) bool {
+	return strings.Contains(field.Tag.Get("config"), "secret")
+}
+
+func jsonFieldName(field reflect.StructField) (name string, ok bool) {
+	tag := field.Tag.Get("json")
+	if tag == "-" {
+		return "", false
+	}
+	name, _, _ = strings.Cut(tag, ",")
+	if name == "" {
+		name = field.Name
+	}
+	return name, true
+}
+
+func redact(value reflect.Value) interface{} {
+	switch value.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if value.IsNil() {
+			return nil
+		}
+		return redact(value.Elem())
+	case reflect.Struct:
+		result := map[string]interface{}{}
+		for i := 0; i < value.NumField(); i++ {
+			field := value.Type().Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			name, ok := jsonFieldName(field)
+			if !ok {
+				continue
+			}
+			if isSecret(field) && !value.Field(i).IsZero() {
+				result[name] = RedactedValue
+			} else {
+				result[name] = redact(value.Field(i))
+			}
+		}
+		return result
+	case reflect.Slice, reflect.Array:
+		if value.Kind() == reflect.Slice && value.IsNil() {
+			return nil
+		}
+		result := []interface{}{}
+		for i := 0; i < value.Len(); i++ {
+			result = append(result, redact(value.Index(i)))
+		}
+		return result
+	case reflect.Map:
+		if value.IsNil() {
+			return nil
+		}
+		result := map[string]interface{}{}
+		iter := value.MapRange()
+		for iter.Next() {
+			result[fmt.Sprint(iter.Key().Interface())] = redact(iter.Value())
+		}
+		return result
+	default:
+		return value.Interface()
+	}
+}
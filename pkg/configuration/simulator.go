@@ -0,0 +1,63 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package configuration
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+)
+
+// the canary device publishes random event values
+const EventPatternRandom = "random"
+
+// the canary device publishes event values incremented by one per event
+const EventPatternIncrement = "increment"
+
+// the canary device publishes DeviceSimulatorEventConstantValue for every event
+const EventPatternConstant = "constant"
+
+// GetDeviceSimulatorEventPattern returns the configured event pattern or EventPatternRandom if none is set
+func (this Config) GetDeviceSimulatorEventPattern() string {
+	if this.DeviceSimulatorEventPattern == "" {
+		return EventPatternRandom
+	}
+	return this.DeviceSimulatorEventPattern
+}
+
+// GetDeviceSimulatorResponseDelay returns the configured delay of command responses; an empty value means no delay
+func (this Config) GetDeviceSimulatorResponseDelay() (time.Duration, error) {
+	if this.DeviceSimulatorResponseDelay == "" {
+		return 0, nil
+	}
+	return time.ParseDuration(this.DeviceSimulatorResponseDelay)
+}
+
+func validateDeviceSimulator(config Config) error {
+	if !slices.Contains([]string{EventPatternRandom, EventPatternIncrement, EventPatternConstant}, config.GetDeviceSimulatorEventPattern()) {
+		return errors.New("invalid device simulator event pattern: " + config.DeviceSimulatorEventPattern)
+	}
+	if config.DeviceSimulatorResponseDropProbability < 0 || config.DeviceSimulatorResponseDropProbability > 1 {
+		return fmt.Errorf("invalid device simulator response drop probability: %v", config.DeviceSimulatorResponseDropProbability)
+	}
+	_, err := config.GetDeviceSimulatorResponseDelay()
+	if err != nil {
+		return fmt.Errorf("invalid device simulator response delay: %w", err)
+	}
+	return nil
+}
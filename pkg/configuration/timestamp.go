@@ -0,0 +1,37 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package configuration
+
+import (
+	"fmt"
+	"time"
+)
+
+func validateConnectorTimestamp(config Config) error {
+	if !config.ConnectorTimestampCheck {
+		return nil
+	}
+	_, err := time.ParseDuration(config.ConnectorTimestampBackdate)
+	if err != nil {
+		return fmt.Errorf("invalid connector_timestamp_backdate: %w", err)
+	}
+	_, err = time.ParseDuration(config.ConnectorTimestampFuture)
+	if err != nil {
+		return fmt.Errorf("invalid connector_timestamp_future: %w", err)
+	}
+	return nil
+}
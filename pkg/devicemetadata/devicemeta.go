@@ -22,6 +22,7 @@ import (
 	"errors"
 	"io"
 	"net/http"
+	"net/url"
 	"runtime/debug"
 	"time"
 
@@ -54,6 +55,8 @@ func (this *DeviceMetaData) EnsureDevice(token string) (device DeviceInfo, err e
 		return device, err
 	}
 	if len(canaryDevices) > 0 {
+		//errors are logged and counted by UpdateOutdatedDeviceType; the checks continue with the existing device-type
+		_ = this.UpdateOutdatedDeviceType(token, canaryDevices[0].DeviceTypeId)
 		return canaryDevices[0], nil
 	} else {
 		return this.CreateCanaryDevice(token)
@@ -123,6 +126,8 @@ func (this *DeviceMetaData) EnsureDeviceType(token string) (result DeviceTypeInf
 		return result, err
 	}
 	if len(canaryDeviceTypes) > 0 {
+		//errors are logged and counted by UpdateOutdatedDeviceType; the checks continue with the existing device-type
+		_ = this.UpdateOutdatedDeviceType(token, canaryDeviceTypes[0].Id)
 		return canaryDeviceTypes[0], nil
 	} else {
 		return this.CreateCanaryDeviceType(token)
@@ -157,16 +162,112 @@ func (this *DeviceMetaData) ListCanaryDeviceTypes(token string) (result []Device
 	return result, err
 }
 
+// UpdateOutdatedDeviceType updates an existing canary device-type if it was created by an older canary version.
+// service ids are kept, to not invalidate existing process deployments and device data.
+// errors are logged and counted in the metrics.
+func (this *DeviceMetaData) UpdateOutdatedDeviceType(token string, deviceTypeId string) error {
+	start := time.Now()
+	existing, err, _ := this.devicerepo.ReadDeviceType(deviceTypeId, token)
+	this.metrics.DeviceRepoRequestCount.Inc()
+	this.metrics.DeviceRepoRequestLatencyMs.Set(float64(time.Since(start).Milliseconds()))
+	if err != nil {
+		this.metrics.DeviceRepoRequestErr.Inc()
+		this.config.GetLogger().Error("unable to read device-type", "error", err)
+		return err
+	}
+	for _, attr := range existing.Attributes {
+		if attr.Key == AttributeCanaryDeviceTypeVersion && attr.Value == CanaryDeviceTypeVersion {
+			return nil
+		}
+	}
+	this.config.GetLogger().Info("update outdated canary device-type", "id", deviceTypeId, "version", CanaryDeviceTypeVersion)
+
+	dt := this.getCanaryDeviceType()
+	dt.Id = existing.Id
+	for i, service := range dt.Services {
+		for _, existingService := range existing.Services {
+			if existingService.LocalId == service.LocalId {
+				dt.Services[i].Id = existingService.Id
+			}
+		}
+	}
+
+	buf := &bytes.Buffer{}
+	err = json.NewEncoder(buf).Encode(dt)
+	if err != nil {
+		this.metrics.UncategorizedErr.Inc()
+		this.config.GetLogger().Error("unable to encode device-type", "error", err)
+		return err
+	}
+	this.metrics.DeviceMetaUpdateCount.Inc()
+	req, err := http.NewRequest(http.MethodPut, this.config.DeviceManagerUrl+"/device-types/"+url.PathEscape(dt.Id)+"?wait=true", buf)
+	if err != nil {
+		this.metrics.UncategorizedErr.Inc()
+		this.config.GetLogger().Error("unable to update device-type", "error", err)
+		return err
+	}
+	req.Header.Set("Authorization", token)
+	start = time.Now()
+	_, _, err = Do[DeviceTypeInfo](req)
+	this.metrics.DeviceMetaUpdateLatencyMs.Set(float64(time.Since(start).Milliseconds()))
+	if err != nil {
+		this.metrics.DeviceMetaUpdateErr.Inc()
+		this.config.GetLogger().Error("unable to update device-type", "error", err)
+		return err
+	}
+	time.Sleep(this.getChangeGuaranteeDuration())
+	return nil
+}
+
 func (this *DeviceMetaData) CreateCanaryDeviceType(token string) (deviceType DeviceTypeInfo, err error) {
+	dt := this.getCanaryDeviceType()
+
+	buf := &bytes.Buffer{}
+	err = json.NewEncoder(buf).Encode(dt)
+	if err != nil {
+		return deviceType, err
+	}
+	this.metrics.DeviceMetaUpdateCount.Inc()
+	req, err := http.NewRequest(http.MethodPost, this.config.DeviceManagerUrl+"/device-types?wait=true", buf)
+	if err != nil {
+		this.metrics.UncategorizedErr.Inc()
+		this.config.GetLogger().Error("unable to create device-type", "error", err)
+		debug.PrintStack()
+		return deviceType, err
+	}
+	req.Header.Set("Authorization", token)
+	start := time.Now()
+	deviceType, _, err = Do[DeviceTypeInfo](req)
+	this.metrics.DeviceMetaUpdateLatencyMs.Set(float64(time.Since(start).Milliseconds()))
+	if err != nil {
+		this.metrics.DeviceMetaUpdateErr.Inc()
+		this.config.GetLogger().Error("unable to create device-type", "error", err)
+		debug.PrintStack()
+	}
+	time.Sleep(this.getChangeGuaranteeDuration())
+	return deviceType, err
+}
+
+// getCanaryDeviceType returns the expected canary device-type without ids.
+// increase CanaryDeviceTypeVersion on changes, so that existing canary device-types are updated.
+func (this *DeviceMetaData) getCanaryDeviceType() models.DeviceType {
 	dt := models.DeviceType{
 		Name:          "canary-device-type",
 		Description:   "used for canary service github.com/SENERGY-Platform/canary",
 		DeviceClassId: this.config.CanaryDeviceClassId,
-		Attributes: []models.Attribute{{
-			Key:    AttributeUsedForCanaryDeviceType,
-			Value:  "true",
-			Origin: "canary",
-		}},
+		Attributes: []models.Attribute{
+			{
+				Key:    AttributeUsedForCanaryDeviceType,
+				Value:  "true",
+				Origin: "canary",
+			},
+			{
+				Key:    AttributeCanaryDeviceTypeVersion,
+				Value:  CanaryDeviceTypeVersion,
+				Origin: "canary",
+			},
+		},
+
 		Services: []models.Service{
 			{
 				LocalId:     CmdServiceLocalId,
@@ -186,6 +287,17 @@ func (this *DeviceMetaData) CreateCanaryDeviceType(token string) (deviceType Dev
 						ProtocolSegmentId: this.config.CanaryProtocolSegmentId,
 					},
 				},
+				Outputs: []models.Content{
+					{
+						ContentVariable: models.ContentVariable{
+							Name:             "value",
+							Type:             models.Type(this.config.CanaryCmdValueType),
+							CharacteristicId: this.config.CanaryCmdCharacteristicId,
+						},
+						Serialization:     models.JSON,
+						ProtocolSegmentId: this.config.CanaryProtocolSegmentId,
+					},
+				},
 			},
 			{
 				LocalId:     SensorServiceLocalId,
@@ -209,31 +321,88 @@ func (this *DeviceMetaData) CreateCanaryDeviceType(token string) (deviceType Dev
 			},
 		},
 	}
+	dt.Services = append(dt.Services, this.getTypedSensorServices()...)
+	dt.Services = append(dt.Services, this.getTimeSensorService(SensorTimePastServiceLocalId), this.getTimeSensorService(SensorTimeFutureServiceLocalId))
+	return dt
+}
 
-	buf := &bytes.Buffer{}
-	err = json.NewEncoder(buf).Encode(dt)
-	if err != nil {
-		return deviceType, err
+// getTimeSensorService returns an event service with a device-reported measurement time at TimeSensorTimePath.
+// back-dated and future values use their own services, because the last-value query returns the value with the latest time.
+func (this *DeviceMetaData) getTimeSensorService(localId string) models.Service {
+	return models.Service{
+		LocalId:     localId,
+		Name:        localId,
+		Description: "canary time sensor service, needed to test device-reported timestamps",
+		Interaction: models.EVENT,
+		ProtocolId:  this.config.CanaryProtocolId,
+		Attributes: []models.Attribute{{
+			Key:    AttributeTimePath,
+			Value:  TimeSensorTimePath,
+			Origin: "canary",
+		}},
+		Outputs: []models.Content{
+			{
+				ContentVariable: models.ContentVariable{
+					Name: "value",
+					Type: models.Structure,
+					SubContentVariables: []models.ContentVariable{
+						{
+							Name:             "value",
+							Type:             models.Type(this.config.CanarySensorValueType),
+							CharacteristicId: this.config.CanarySensorCharacteristicId,
+						},
+						{
+							Name: "time",
+							Type: models.String,
+						},
+					},
+				},
+				Serialization:     models.JSON,
+				ProtocolSegmentId: this.config.CanaryProtocolSegmentId,
+			},
+		},
 	}
-	this.metrics.DeviceMetaUpdateCount.Inc()
-	req, err := http.NewRequest(http.MethodPost, this.config.DeviceManagerUrl+"/device-types?wait=true", buf)
-	if err != nil {
-		this.metrics.UncategorizedErr.Inc()
-		this.config.GetLogger().Error("unable to create device-type", "error", err)
-		debug.PrintStack()
-		return deviceType, err
+}
+
+// getTypedSensorServices returns event services for the value types not covered by the integer sensor service
+func (this *DeviceMetaData) getTypedSensorServices() (result []models.Service) {
+	variables := map[string]models.ContentVariable{
+		SensorFloatServiceLocalId:  {Name: "value", Type: models.Float},
+		SensorStringServiceLocalId: {Name: "value", Type: models.String},
+		SensorBoolServiceLocalId:   {Name: "value", Type: models.Boolean},
+		SensorStructServiceLocalId: {
+			Name: "value",
+			Type: models.Structure,
+			SubContentVariables: []models.ContentVariable{
+				{Name: "text", Type: models.String},
+				{
+					Name: "inner",
+					Type: models.Structure,
+					SubContentVariables: []models.ContentVariable{
+						{Name: "number", Type: models.Float},
+						{Name: "flag", Type: models.Boolean},
+					},
+				},
+			},
+		},
 	}
-	req.Header.Set("Authorization", token)
-	start := time.Now()
-	deviceType, _, err = Do[DeviceTypeInfo](req)
-	this.metrics.DeviceMetaUpdateLatencyMs.Set(float64(time.Since(start).Milliseconds()))
-	if err != nil {
-		this.metrics.DeviceMetaUpdateErr.Inc()
-		this.config.GetLogger().Error("unable to create device-type", "error", err)
-		debug.PrintStack()
+	for _, localId := range TypedSensorServiceLocalIds {
+		result = append(result, models.Service{
+			LocalId:     localId,
+			Name:        localId,
+			Description: "canary typed sensor service, needed to test device data handling of " + string(variables[localId].Type) + " values",
+			Interaction: models.EVENT,
+			ProtocolId:  this.config.CanaryProtocolId,
+			Outputs: []models.Content{
+				{
+					ContentVariable:   variables[localId],
+					Serialization:     models.JSON,
+					ProtocolSegmentId: this.config.CanaryProtocolSegmentId,
+				},
+			},
+		})
 	}
-	time.Sleep(this.getChangeGuaranteeDuration())
-	return deviceType, err
+	return result
 }
 
 func Do[T any](req *http.Request) (result T, code int, err error) {
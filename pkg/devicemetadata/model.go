@@ -32,5 +32,28 @@ type PermDevice struct {
 
 const AttributeUsedForCanaryDevice = "senergy/canary-device"
 const AttributeUsedForCanaryDeviceType = "senergy/canary-device-type"
+const AttributeCanaryDeviceTypeVersion = "senergy/canary-device-type-version"
+
+// CanaryDeviceTypeVersion is stored in the AttributeCanaryDeviceTypeVersion attribute of the canary device-type.
+// version 2: the cmd service responds with a value
+// version 3: typed sensor services
+// version 4: time sensor services
+const CanaryDeviceTypeVersion = "4"
 const SensorServiceLocalId = "sensor"
 const CmdServiceLocalId = "cmd"
+const SensorFloatServiceLocalId = "sensor_float"
+const SensorStringServiceLocalId = "sensor_string"
+const SensorBoolServiceLocalId = "sensor_bool"
+const SensorStructServiceLocalId = "sensor_struct"
+
+const SensorTimePastServiceLocalId = "sensor_time_past"
+const SensorTimeFutureServiceLocalId = "sensor_time_future"
+
+// AttributeTimePath marks the content variable containing the measurement time of a service
+const AttributeTimePath = "senergy/time_path"
+
+// TimeSensorTimePath is the path of the time content variable of the time sensor services
+const TimeSensorTimePath = "value.time"
+
+// TypedSensorServiceLocalIds lists the event services of the typed value check
+var TypedSensorServiceLocalIds = []string{SensorFloatServiceLocalId, SensorStringServiceLocalId, SensorBoolServiceLocalId, SensorStructServiceLocalId}
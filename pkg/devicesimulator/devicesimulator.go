@@ -0,0 +1,92 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package devicesimulator
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/SENERGY-Platform/canary/pkg/configuration"
+)
+
+// maxRandomValue bounds random values; they stay exact after the conversion to float64 by json decoders of the platform
+const maxRandomValue = 1_000_000
+
+// DeviceSimulator decides the values and the behavior of the simulated canary device.
+// it does not communicate with the platform; the connector check publishes the results.
+type DeviceSimulator struct {
+	config        configuration.Config
+	responseDelay time.Duration
+	mux           sync.Mutex
+	responseIndex int
+	eventValue    int
+	rand          *rand.Rand
+}
+
+// Response describes how the simulated device answers a command
+type Response struct {
+	Value   int
+	Delay   time.Duration
+	Dropped bool
+}
+
+func New(config configuration.Config) (*DeviceSimulator, error) {
+	responseDelay, err := config.GetDeviceSimulatorResponseDelay()
+	if err != nil {
+		return nil, err
+	}
+	return &DeviceSimulator{
+		config:        config,
+		responseDelay: responseDelay,
+		eventValue:    rand.Intn(maxRandomValue),
+		rand:          rand.New(rand.NewSource(time.Now().UnixNano())),
+	}, nil
+}
+
+// NextEventValue returns the value of the next sensor event according to the configured event pattern
+func (this *DeviceSimulator) NextEventValue() int {
+	this.mux.Lock()
+	defer this.mux.Unlock()
+	switch this.config.GetDeviceSimulatorEventPattern() {
+	case configuration.EventPatternConstant:
+		return this.config.DeviceSimulatorEventConstantValue
+	case configuration.EventPatternIncrement:
+		this.eventValue++
+		return this.eventValue
+	default:
+		return this.rand.Intn(maxRandomValue)
+	}
+}
+
+// NextResponse returns the answer to the next command.
+// the configured response values are used in turns; without configured values a random value is used.
+func (this *DeviceSimulator) NextResponse() Response {
+	this.mux.Lock()
+	defer this.mux.Unlock()
+	result := Response{
+		Delay:   this.responseDelay,
+		Dropped: this.rand.Float64() < this.config.DeviceSimulatorResponseDropProbability,
+	}
+	if len(this.config.DeviceSimulatorResponseValues) == 0 {
+		result.Value = this.rand.Intn(maxRandomValue)
+	} else {
+		result.Value = this.config.DeviceSimulatorResponseValues[this.responseIndex%len(this.config.DeviceSimulatorResponseValues)]
+		this.responseIndex++
+	}
+	return result
+}
@@ -0,0 +1,74 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package devicesimulator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/SENERGY-Platform/canary/pkg/configuration"
+)
+
+func TestNextEventValue(t *testing.T) {
+	sim, err := New(configuration.Config{DeviceSimulatorEventPattern: configuration.EventPatternIncrement})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	first := sim.NextEventValue()
+	if second := sim.NextEventValue(); second != first+1 {
+		t.Error("unexpected increment", first, second)
+	}
+
+	sim, err = New(configuration.Config{DeviceSimulatorEventPattern: configuration.EventPatternConstant, DeviceSimulatorEventConstantValue: 42})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if value := sim.NextEventValue(); value != 42 {
+		t.Error("unexpected constant", value)
+	}
+}
+
+func TestNextResponse(t *testing.T) {
+	sim, err := New(configuration.Config{
+		DeviceSimulatorResponseValues: []int{1, 2},
+		DeviceSimulatorResponseDelay:  "2s",
+	})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	for _, expected := range []int{1, 2, 1} {
+		resp := sim.NextResponse()
+		if resp.Value != expected || resp.Delay != 2*time.Second || resp.Dropped {
+			t.Error("unexpected response", resp, expected)
+		}
+	}
+
+	sim, err = New(configuration.Config{DeviceSimulatorResponseDropProbability: 1})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if resp := sim.NextResponse(); !resp.Dropped {
+		t.Error("expected dropped response", resp)
+	}
+	if resp := sim.NextResponse(); resp.Value < 0 || resp.Value >= maxRandomValue {
+		t.Error("unexpected random response value", resp)
+	}
+}
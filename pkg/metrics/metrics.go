@@ -25,6 +25,26 @@ type Metrics struct {
 	AuthLatencyMs prometheus.Gauge
 	AuthErr       prometheus.Counter
 
+	AuthRefreshCount     prometheus.Counter
+	AuthRefreshLatencyMs prometheus.Gauge
+	AuthRefreshErr       prometheus.Counter
+
+	AuthLogoutCount     prometheus.Counter
+	AuthLogoutLatencyMs prometheus.Gauge
+	AuthLogoutErr       prometheus.Counter
+
+	AuthCheckLoginCount     prometheus.Counter
+	AuthCheckLoginLatencyMs prometheus.Gauge
+	AuthCheckLoginErr       prometheus.Counter
+
+	AuthTokenValidationCount prometheus.Counter
+	AuthTokenValidationErr   prometheus.Counter
+	AuthTokenClockSkewMs     prometheus.Gauge
+
+	AuthRevocationCheckCount          prometheus.Counter
+	AuthRevocationCheckErr            prometheus.Counter
+	UnexpectedRevokedTokenAcceptedErr prometheus.Counter
+
 	DeviceMetaUpdateCount     prometheus.Counter
 	DeviceMetaUpdateLatencyMs prometheus.Gauge
 	DeviceMetaUpdateErr       prometheus.Counter
@@ -33,21 +53,74 @@ type Metrics struct {
 	DeviceRepoRequestLatencyMs prometheus.Gauge
 	DeviceRepoRequestErr       prometheus.Counter
 
-	DeviceDataRequestCount     prometheus.Counter
-	DeviceDataRequestLatencyMs prometheus.Gauge
-	DeviceDataRequestErr       prometheus.Counter
+	DeviceDataRequestCount     *prometheus.CounterVec
+	DeviceDataRequestLatencyMs *prometheus.GaugeVec
+	DeviceDataRequestErr       *prometheus.CounterVec
+
+	ConnectorLoginCount     *prometheus.CounterVec
+	ConnectorLoginLatencyMs *prometheus.GaugeVec
+	ConnectorLoginErr       *prometheus.CounterVec
+
+	ConnectorSubscribeCount     *prometheus.CounterVec
+	ConnectorSubscribeLatencyMs *prometheus.GaugeVec
+	ConnectorSubscribeErr       *prometheus.CounterVec
+
+	ConnectorPublishCount     *prometheus.CounterVec
+	ConnectorPublishLatencyMs *prometheus.GaugeVec
+	ConnectorPublishErr       *prometheus.CounterVec
+
+	ConnectorReasonCode *prometheus.CounterVec
+
+	ConnectorCommandResponseCount     *prometheus.CounterVec
+	ConnectorCommandResponseLatencyMs *prometheus.GaugeVec
+	ConnectorCommandResponseErr       *prometheus.CounterVec
+
+	ConnectorConnectionLostCount *prometheus.CounterVec
+	ConnectorReconnectCount      *prometheus.CounterVec
+	ConnectorReconnectLatencyMs  *prometheus.GaugeVec
+
+	ConnectorOfflineDetectionLatencyMs *prometheus.GaugeVec
+	ConnectorOfflineDetectionSlaErr    *prometheus.CounterVec
+
+	ConnectorAclCheckCount   *prometheus.CounterVec
+	ConnectorAclViolationErr *prometheus.CounterVec
+
+	TypedDeviceDataCheckCount    *prometheus.CounterVec
+	UnexpectedTypedDeviceDataErr *prometheus.CounterVec
 
-	ConnectorLoginCount     prometheus.Counter
-	ConnectorLoginLatencyMs prometheus.Gauge
-	ConnectorLoginErr       prometheus.Counter
+	ConversionCheckCount        *prometheus.CounterVec
+	UnexpectedConvertedValueErr *prometheus.CounterVec
 
-	ConnectorSubscribeCount     prometheus.Counter
-	ConnectorSubscribeLatencyMs prometheus.Gauge
-	ConnectorSubscribeErr       prometheus.Counter
+	DeviceTimestampCheckCount    *prometheus.CounterVec
+	UnexpectedDeviceTimestampErr *prometheus.CounterVec
 
-	ConnectorPublishCount     prometheus.Counter
-	ConnectorPublishLatencyMs prometheus.Gauge
-	ConnectorPublishErr       prometheus.Counter
+	BurstCheckCount             prometheus.Counter
+	BurstQueryErr               prometheus.Counter
+	BurstLostMessageCount       prometheus.Counter
+	BurstDuplicatedMessageCount prometheus.Counter
+	BurstOutOfOrderMessageCount prometheus.Counter
+
+	HistoricQueryCount             prometheus.Counter
+	HistoricQueryLatencyMs         prometheus.Gauge
+	HistoricQueryErr               prometheus.Counter
+	UnexpectedHistoricDataErr      prometheus.Counter
+	UnexpectedHistoricAggregateErr prometheus.Counter
+
+	ClientCertExpiryTimestampSeconds prometheus.Gauge
+	ClientCertMismatchCount          prometheus.Counter
+
+	CertAuthorityStepCount     *prometheus.CounterVec
+	CertAuthorityStepLatencyMs *prometheus.GaugeVec
+	CertAuthorityStepErr       *prometheus.CounterVec
+
+	DeviceCommandCount                 prometheus.Counter
+	DeviceCommandLatencyMs             prometheus.Gauge
+	DeviceCommandErr                   prometheus.Counter
+	UnexpectedDeviceCommandResponseErr prometheus.Counter
+
+	DeviceErrorCount                     prometheus.Counter
+	DeviceErrorPublishErr                prometheus.Counter
+	UnexpectedDeviceErrorNotificationErr prometheus.Counter
 
 	NotificationPublishCount     prometheus.Counter
 	NotificationPublishLatencyMs prometheus.Gauge
@@ -61,10 +134,10 @@ type Metrics struct {
 	NotificationDeleteLatencyMs prometheus.Gauge
 	NotificationDeleteErr       prometheus.Counter
 
-	UnexpectedDeviceOnlineStateErr  prometheus.Counter
-	UnexpectedDeviceOfflineStateErr prometheus.Counter
+	UnexpectedDeviceOnlineStateErr  *prometheus.CounterVec
+	UnexpectedDeviceOfflineStateErr *prometheus.CounterVec
 	UnexpectedDeviceRepoMetadataErr prometheus.Counter
-	UnexpectedDeviceDataErr         prometheus.Counter
+	UnexpectedDeviceDataErr         *prometheus.CounterVec
 	UnexpectedNotificationStateErr  prometheus.Counter
 	UncategorizedErr                prometheus.Counter
 
@@ -75,6 +148,9 @@ type Metrics struct {
 	ProcessInstanceDurationMs                         prometheus.Gauge
 	ProcessPreparedDeploymentErr                      prometheus.Counter
 	ProcessUnexpectedPreparedDeploymentSelectablesErr prometheus.Counter
+	ProcessMissingIncidentErr                         prometheus.Counter
+	ProcessUnexpectedResponseValueErr                 prometheus.Counter
+	DeviceSimulatorDroppedResponseCount               prometheus.Counter
 
 	EventProcessDeploymentErr                              prometheus.Counter
 	UnexpectedEventProcessInstanceStateErr                 prometheus.Counter
@@ -83,6 +159,33 @@ type Metrics struct {
 	EventProcessUnexpectedPreparedDeploymentSelectablesErr prometheus.Counter
 }
 
+// TypedValueLabels are used by metrics of the typed value check
+var TypedValueLabels = []string{"value_type"}
+
+// ConversionLabels are used by metrics of the characteristic conversion check; api is "last_value" or "device_command"
+var ConversionLabels = []string{"api"}
+
+// TimestampLabels are used by metrics of the device timestamp check; timestamp is "past" or "future"
+var TimestampLabels = []string{"timestamp"}
+
+// CertAuthorityStepLabels are used by metrics of the certificate authority lifecycle check; step is "issue", "connect", "revoke" or "refuse"
+var CertAuthorityStepLabels = []string{"step"}
+
+// ConnectorLabels are used by metrics of checks that run per connector broker
+var ConnectorLabels = []string{"broker", "transport", "protocol"}
+
+// ConnectorQosLabels are used by metrics of checks that run per connector broker and mqtt qos level
+var ConnectorQosLabels = []string{"broker", "transport", "protocol", "qos"}
+
+// ConnectorReasonCodeLabels are used to count the reason codes of mqtt acknowledgement packets
+var ConnectorReasonCodeLabels = []string{"broker", "transport", "protocol", "packet", "reason_code"}
+
+// ConnectorDisconnectLabels are used by offline detection metrics; disconnect is "clean" or "ungraceful"
+var ConnectorDisconnectLabels = []string{"broker", "transport", "protocol", "disconnect"}
+
+// ConnectorAclLabels are used by connector authorization metrics; attempt names the forbidden operation
+var ConnectorAclLabels = []string{"broker", "transport", "protocol", "attempt"}
+
 func NewMetrics(reg prometheus.Registerer) *Metrics {
 	const countHelpMsg = "how often has this test ben started. this value is used to indicate if a test has ben started and no error has ben found ore no test has ben started."
 	m := &Metrics{
@@ -98,6 +201,66 @@ func NewMetrics(reg prometheus.Registerer) *Metrics {
 			Name: "canary_auth_err",
 			Help: "total count of auth errors since canary startup",
 		}),
+		AuthRefreshCount: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "canary_auth_refresh_count",
+			Help: countHelpMsg,
+		}),
+		AuthRefreshLatencyMs: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "canary_auth_refresh_latency_ms",
+			Help: "latency of auth token refresh request",
+		}),
+		AuthRefreshErr: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "canary_auth_refresh_err",
+			Help: "total count of auth token refresh errors since canary startup",
+		}),
+		AuthLogoutCount: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "canary_auth_logout_count",
+			Help: countHelpMsg,
+		}),
+		AuthLogoutLatencyMs: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "canary_auth_logout_latency_ms",
+			Help: "latency of auth logout request",
+		}),
+		AuthLogoutErr: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "canary_auth_logout_err",
+			Help: "total count of auth logout errors since canary startup",
+		}),
+		AuthCheckLoginCount: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "canary_auth_check_login_count",
+			Help: countHelpMsg,
+		}),
+		AuthCheckLoginLatencyMs: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "canary_auth_check_login_latency_ms",
+			Help: "latency of the login of the auth check",
+		}),
+		AuthCheckLoginErr: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "canary_auth_check_login_err",
+			Help: "total count of auth check login errors since canary startup",
+		}),
+		AuthTokenValidationCount: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "canary_auth_token_validation_count",
+			Help: countHelpMsg,
+		}),
+		AuthTokenValidationErr: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "canary_auth_token_validation_err",
+			Help: "total count of invalid access tokens (signature, claims, roles) since canary startup",
+		}),
+		AuthTokenClockSkewMs: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "canary_auth_token_clock_skew_ms",
+			Help: "difference between local token request time and the tokens iat claim; positive if the local clock is ahead",
+		}),
+		AuthRevocationCheckCount: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "canary_auth_revocation_check_count",
+			Help: countHelpMsg,
+		}),
+		AuthRevocationCheckErr: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "canary_auth_revocation_check_err",
+			Help: "total count of auth revocation check errors since canary startup",
+		}),
+		UnexpectedRevokedTokenAcceptedErr: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "canary_unexpected_revoked_token_accepted_err",
+			Help: "total count of platform api requests accepted with a token of a logged out session since canary startup",
+		}),
 		DeviceMetaUpdateCount: prometheus.NewCounter(prometheus.CounterOpts{
 			Name: "canary_device_meta_update_count",
 			Help: countHelpMsg,
@@ -122,53 +285,209 @@ func NewMetrics(reg prometheus.Registerer) *Metrics {
 			Name: "canary_device_repo_request_update_err",
 			Help: "total count of device repo request errors since canary startup",
 		}),
-		DeviceDataRequestCount: prometheus.NewCounter(prometheus.CounterOpts{
+		DeviceDataRequestCount: prometheus.NewCounterVec(prometheus.CounterOpts{
 			Name: "canary_device_data_request_count",
 			Help: countHelpMsg,
-		}),
-		DeviceDataRequestLatencyMs: prometheus.NewGauge(prometheus.GaugeOpts{
+		}, ConnectorQosLabels),
+		DeviceDataRequestLatencyMs: prometheus.NewGaugeVec(prometheus.GaugeOpts{
 			Name: "canary_device_data_request_latency_ms",
 			Help: "latency of device data request",
-		}),
-		DeviceDataRequestErr: prometheus.NewCounter(prometheus.CounterOpts{
+		}, ConnectorQosLabels),
+		DeviceDataRequestErr: prometheus.NewCounterVec(prometheus.CounterOpts{
 			Name: "canary_device_data_request_update_err",
 			Help: "total count of device data request errors since canary startup",
-		}),
-		ConnectorLoginCount: prometheus.NewCounter(prometheus.CounterOpts{
+		}, ConnectorQosLabels),
+		ConnectorLoginCount: prometheus.NewCounterVec(prometheus.CounterOpts{
 			Name: "canary_connector_login_count",
 			Help: countHelpMsg,
-		}),
-		ConnectorLoginLatencyMs: prometheus.NewGauge(prometheus.GaugeOpts{
+		}, ConnectorLabels),
+		ConnectorLoginLatencyMs: prometheus.NewGaugeVec(prometheus.GaugeOpts{
 			Name: "canary_connector_login_latency_ms",
 			Help: "latency of connector login",
-		}),
-		ConnectorLoginErr: prometheus.NewCounter(prometheus.CounterOpts{
+		}, ConnectorLabels),
+		ConnectorLoginErr: prometheus.NewCounterVec(prometheus.CounterOpts{
 			Name: "canary_connector_login_err",
 			Help: "total count of connector login errors since canary startup",
-		}),
-		ConnectorSubscribeCount: prometheus.NewCounter(prometheus.CounterOpts{
+		}, ConnectorLabels),
+		ConnectorSubscribeCount: prometheus.NewCounterVec(prometheus.CounterOpts{
 			Name: "canary_connector_subscribe_count",
 			Help: countHelpMsg,
-		}),
-		ConnectorSubscribeLatencyMs: prometheus.NewGauge(prometheus.GaugeOpts{
+		}, ConnectorLabels),
+		ConnectorSubscribeLatencyMs: prometheus.NewGaugeVec(prometheus.GaugeOpts{
 			Name: "canary_connector_subscribe_latency_ms",
 			Help: "latency of connector subscribe",
-		}),
-		ConnectorSubscribeErr: prometheus.NewCounter(prometheus.CounterOpts{
+		}, ConnectorLabels),
+		ConnectorSubscribeErr: prometheus.NewCounterVec(prometheus.CounterOpts{
 			Name: "canary_connector_subscribe_err",
 			Help: "total count of connector subscribe errors since canary startup",
-		}),
-		ConnectorPublishCount: prometheus.NewCounter(prometheus.CounterOpts{
+		}, ConnectorLabels),
+		ConnectorPublishCount: prometheus.NewCounterVec(prometheus.CounterOpts{
 			Name: "canary_connector_publish_count",
 			Help: countHelpMsg,
-		}),
-		ConnectorPublishLatencyMs: prometheus.NewGauge(prometheus.GaugeOpts{
+		}, ConnectorQosLabels),
+		ConnectorPublishLatencyMs: prometheus.NewGaugeVec(prometheus.GaugeOpts{
 			Name: "canary_connector_publish_latency_ms",
 			Help: "latency of connector publish",
-		}),
-		ConnectorPublishErr: prometheus.NewCounter(prometheus.CounterOpts{
+		}, ConnectorQosLabels),
+		ConnectorPublishErr: prometheus.NewCounterVec(prometheus.CounterOpts{
 			Name: "canary_connector_publish_err",
 			Help: "total count of connector publish errors since canary startup",
+		}, ConnectorQosLabels),
+		ConnectorReasonCode: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "canary_connector_reason_code",
+			Help: "total count of reason codes received in connack, suback and puback packets since canary startup",
+		}, ConnectorReasonCodeLabels),
+		ConnectorCommandResponseCount: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "canary_connector_command_response_count",
+			Help: countHelpMsg,
+		}, ConnectorQosLabels),
+		ConnectorCommandResponseLatencyMs: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "canary_connector_command_response_latency_ms",
+			Help: "time between receiving a command and the acknowledgement of its response",
+		}, ConnectorQosLabels),
+		ConnectorCommandResponseErr: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "canary_connector_command_response_err",
+			Help: "total count of connector command response errors since canary startup",
+		}, ConnectorQosLabels),
+		ConnectorConnectionLostCount: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "canary_connector_connection_lost_count",
+			Help: "total count of unexpectedly lost connector connections since canary startup",
+		}, ConnectorLabels),
+		ConnectorReconnectCount: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "canary_connector_reconnect_count",
+			Help: "total count of connector reconnect attempts since canary startup",
+		}, ConnectorLabels),
+		ConnectorReconnectLatencyMs: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "canary_connector_reconnect_latency_ms",
+			Help: "time between the last lost connector connection and the successful reconnect",
+		}, ConnectorLabels),
+		ConnectorOfflineDetectionLatencyMs: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "canary_connector_offline_detection_latency_ms",
+			Help: "time between closing the connector connection and the device repository reporting the device as offline",
+		}, ConnectorDisconnectLabels),
+		ConnectorOfflineDetectionSlaErr: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "canary_connector_offline_detection_sla_err",
+			Help: "total count of offline detections exceeding connector_offline_detection_sla since canary startup",
+		}, ConnectorDisconnectLabels),
+		ConnectorAclCheckCount: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "canary_connector_acl_check_count",
+			Help: countHelpMsg,
+		}, ConnectorAclLabels),
+		ConnectorAclViolationErr: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "canary_connector_acl_violation_err",
+			Help: "security relevant: total count of forbidden connector operations that have not been refused since canary startup",
+		}, ConnectorAclLabels),
+		TypedDeviceDataCheckCount: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "canary_typed_device_data_check_count",
+			Help: countHelpMsg,
+		}, TypedValueLabels),
+		UnexpectedTypedDeviceDataErr: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "canary_unexpected_typed_device_data_err",
+			Help: "total count of unexpected device data values of the typed value check since canary startup",
+		}, TypedValueLabels),
+		ConversionCheckCount: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "canary_conversion_check_count",
+			Help: countHelpMsg,
+		}, ConversionLabels),
+		UnexpectedConvertedValueErr: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "canary_unexpected_converted_value_err",
+			Help: "total count of missing or unexpected values converted to connector_conversion_target_characteristic_id since canary startup",
+		}, ConversionLabels),
+		DeviceTimestampCheckCount: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "canary_device_timestamp_check_count",
+			Help: countHelpMsg,
+		}, TimestampLabels),
+		UnexpectedDeviceTimestampErr: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "canary_unexpected_device_timestamp_err",
+			Help: "total count of last values not reflecting the device-reported timestamp since canary startup",
+		}, TimestampLabels),
+		BurstCheckCount: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "canary_burst_check_count",
+			Help: countHelpMsg,
+		}),
+		BurstQueryErr: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "canary_burst_query_err",
+			Help: "total count of failed historic data queries of the burst check since canary startup",
+		}),
+		BurstLostMessageCount: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "canary_burst_lost_message_count",
+			Help: "total count of burst events missing in the historic data since canary startup",
+		}),
+		BurstDuplicatedMessageCount: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "canary_burst_duplicated_message_count",
+			Help: "total count of duplicated burst events in the historic data since canary startup",
+		}),
+		BurstOutOfOrderMessageCount: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "canary_burst_out_of_order_message_count",
+			Help: "total count of burst events stored out of publish order in the historic data since canary startup",
+		}),
+		HistoricQueryCount: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "canary_historic_query_count",
+			Help: countHelpMsg,
+		}),
+		HistoricQueryLatencyMs: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "canary_historic_query_latency_ms",
+			Help: "latency of historic data query",
+		}),
+		HistoricQueryErr: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "canary_historic_query_err",
+			Help: "total count of failed historic data queries since canary startup",
+		}),
+		UnexpectedHistoricDataErr: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "canary_unexpected_historic_data_err",
+			Help: "total count of published sensor values missing in the historic data since canary startup",
+		}),
+		UnexpectedHistoricAggregateErr: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "canary_unexpected_historic_aggregate_err",
+			Help: "total count of historic mean aggregates not matching the locally computed mean since canary startup",
+		}),
+		ClientCertExpiryTimestampSeconds: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "canary_client_cert_expiry_timestamp_seconds",
+			Help: "expiry of the client cert used to connect to the connector as unix timestamp",
+		}),
+		ClientCertMismatchCount: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "canary_client_cert_mismatch_count",
+			Help: "total count of cached client certs not matching the hub id or the configured ca since canary startup",
+		}),
+		CertAuthorityStepCount: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "canary_cert_authority_step_count",
+			Help: countHelpMsg,
+		}, CertAuthorityStepLabels),
+		CertAuthorityStepLatencyMs: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "canary_cert_authority_step_latency_ms",
+			Help: "latency of the last certificate authority lifecycle step",
+		}, CertAuthorityStepLabels),
+		CertAuthorityStepErr: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "canary_cert_authority_step_err",
+			Help: "total count of failed certificate authority lifecycle steps since canary startup; a failed refuse step means a revoked cert has been accepted",
+		}, CertAuthorityStepLabels),
+		DeviceCommandCount: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "canary_device_command_count",
+			Help: countHelpMsg,
+		}),
+		DeviceCommandLatencyMs: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "canary_device_command_latency_ms",
+			Help: "round trip latency of a synchronous command to the canary device over the device-command api",
+		}),
+		DeviceCommandErr: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "canary_device_command_err",
+			Help: "total count of failed device-command requests since canary startup",
+		}),
+		UnexpectedDeviceCommandResponseErr: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "canary_unexpected_device_command_response_err",
+			Help: "total count of device-command results not matching the response of the canary device since canary startup",
+		}),
+		DeviceErrorCount: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "canary_device_error_count",
+			Help: countHelpMsg,
+		}),
+		DeviceErrorPublishErr: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "canary_device_error_publish_err",
+			Help: "total count of device error publish errors since canary startup",
+		}),
+		UnexpectedDeviceErrorNotificationErr: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "canary_unexpected_device_error_notification_err",
+			Help: "total count of device errors without matching notification since canary startup",
 		}),
 		NotificationPublishCount: prometheus.NewCounter(prometheus.CounterOpts{
 			Name: "canary_notification_publish_count",
@@ -206,22 +525,22 @@ func NewMetrics(reg prometheus.Registerer) *Metrics {
 			Name: "canary_notification_delete_err",
 			Help: "total count of notification delete errors since canary startup",
 		}),
-		UnexpectedDeviceOnlineStateErr: prometheus.NewCounter(prometheus.CounterOpts{
+		UnexpectedDeviceOnlineStateErr: prometheus.NewCounterVec(prometheus.CounterOpts{
 			Name: "canary_unexpected_device_online_state_err",
 			Help: "total count of unexpected device online state errors since canary startup",
-		}),
-		UnexpectedDeviceOfflineStateErr: prometheus.NewCounter(prometheus.CounterOpts{
+		}, ConnectorLabels),
+		UnexpectedDeviceOfflineStateErr: prometheus.NewCounterVec(prometheus.CounterOpts{
 			Name: "canary_unexpected_device_offline_state_err",
 			Help: "total count of unexpected device offline state errors since canary startup",
-		}),
+		}, ConnectorLabels),
 		UnexpectedDeviceRepoMetadataErr: prometheus.NewCounter(prometheus.CounterOpts{
 			Name: "canary_unexpected_device_repo_metadata_err",
 			Help: "total count of unexpected device repo metadata value errors since canary startup",
 		}),
-		UnexpectedDeviceDataErr: prometheus.NewCounter(prometheus.CounterOpts{
+		UnexpectedDeviceDataErr: prometheus.NewCounterVec(prometheus.CounterOpts{
 			Name: "canary_unexpected_device_data_err",
 			Help: "total count of unexpected device data value errors since canary startup",
-		}),
+		}, ConnectorQosLabels),
 		UnexpectedNotificationStateErr: prometheus.NewCounter(prometheus.CounterOpts{
 			Name: "canary_unexpected_notification_state_err",
 			Help: "total count of unexpected notification state errors since canary startup",
@@ -258,6 +577,18 @@ func NewMetrics(reg prometheus.Registerer) *Metrics {
 			Name: "canary_unexpected_prepared_deployment_selectables_err",
 			Help: "total count of prepared process selectable errors since canary startup",
 		}),
+		ProcessMissingIncidentErr: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "canary_process_missing_incident_err",
+			Help: "total count of process instances without expected incident after a command error or missing command response since canary startup",
+		}),
+		ProcessUnexpectedResponseValueErr: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "canary_process_unexpected_response_value_err",
+			Help: "total count of process instances without the command response value in the process variables since canary startup",
+		}),
+		DeviceSimulatorDroppedResponseCount: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "canary_device_simulator_dropped_response_count",
+			Help: "total count of command responses dropped by the device simulator since canary startup",
+		}),
 
 		EventProcessDeploymentErr: prometheus.NewCounter(prometheus.CounterOpts{
 			Name: "canary_event_process_deployment_err",
@@ -285,6 +616,26 @@ func NewMetrics(reg prometheus.Registerer) *Metrics {
 	reg.MustRegister(m.AuthLatencyMs)
 	reg.MustRegister(m.AuthErr)
 
+	reg.MustRegister(m.AuthRefreshCount)
+	reg.MustRegister(m.AuthRefreshLatencyMs)
+	reg.MustRegister(m.AuthRefreshErr)
+
+	reg.MustRegister(m.AuthLogoutCount)
+	reg.MustRegister(m.AuthLogoutLatencyMs)
+	reg.MustRegister(m.AuthLogoutErr)
+
+	reg.MustRegister(m.AuthCheckLoginCount)
+	reg.MustRegister(m.AuthCheckLoginLatencyMs)
+	reg.MustRegister(m.AuthCheckLoginErr)
+
+	reg.MustRegister(m.AuthTokenValidationCount)
+	reg.MustRegister(m.AuthTokenValidationErr)
+	reg.MustRegister(m.AuthTokenClockSkewMs)
+
+	reg.MustRegister(m.AuthRevocationCheckCount)
+	reg.MustRegister(m.AuthRevocationCheckErr)
+	reg.MustRegister(m.UnexpectedRevokedTokenAcceptedErr)
+
 	reg.MustRegister(m.DeviceMetaUpdateCount)
 	reg.MustRegister(m.DeviceMetaUpdateLatencyMs)
 	reg.MustRegister(m.DeviceMetaUpdateErr)
@@ -309,6 +660,59 @@ func NewMetrics(reg prometheus.Registerer) *Metrics {
 	reg.MustRegister(m.ConnectorPublishLatencyMs)
 	reg.MustRegister(m.ConnectorPublishErr)
 
+	reg.MustRegister(m.ConnectorReasonCode)
+
+	reg.MustRegister(m.ConnectorCommandResponseCount)
+	reg.MustRegister(m.ConnectorCommandResponseLatencyMs)
+	reg.MustRegister(m.ConnectorCommandResponseErr)
+
+	reg.MustRegister(m.ConnectorConnectionLostCount)
+	reg.MustRegister(m.ConnectorReconnectCount)
+	reg.MustRegister(m.ConnectorReconnectLatencyMs)
+
+	reg.MustRegister(m.ConnectorOfflineDetectionLatencyMs)
+	reg.MustRegister(m.ConnectorOfflineDetectionSlaErr)
+
+	reg.MustRegister(m.ConnectorAclCheckCount)
+	reg.MustRegister(m.ConnectorAclViolationErr)
+
+	reg.MustRegister(m.TypedDeviceDataCheckCount)
+	reg.MustRegister(m.UnexpectedTypedDeviceDataErr)
+
+	reg.MustRegister(m.ConversionCheckCount)
+	reg.MustRegister(m.UnexpectedConvertedValueErr)
+
+	reg.MustRegister(m.DeviceTimestampCheckCount)
+	reg.MustRegister(m.UnexpectedDeviceTimestampErr)
+
+	reg.MustRegister(m.BurstCheckCount)
+	reg.MustRegister(m.BurstQueryErr)
+	reg.MustRegister(m.BurstLostMessageCount)
+	reg.MustRegister(m.BurstDuplicatedMessageCount)
+	reg.MustRegister(m.BurstOutOfOrderMessageCount)
+
+	reg.MustRegister(m.HistoricQueryCount)
+	reg.MustRegister(m.HistoricQueryLatencyMs)
+	reg.MustRegister(m.HistoricQueryErr)
+	reg.MustRegister(m.UnexpectedHistoricDataErr)
+	reg.MustRegister(m.UnexpectedHistoricAggregateErr)
+
+	reg.MustRegister(m.ClientCertExpiryTimestampSeconds)
+	reg.MustRegister(m.ClientCertMismatchCount)
+
+	reg.MustRegister(m.CertAuthorityStepCount)
+	reg.MustRegister(m.CertAuthorityStepLatencyMs)
+	reg.MustRegister(m.CertAuthorityStepErr)
+
+	reg.MustRegister(m.DeviceCommandCount)
+	reg.MustRegister(m.DeviceCommandLatencyMs)
+	reg.MustRegister(m.DeviceCommandErr)
+	reg.MustRegister(m.UnexpectedDeviceCommandResponseErr)
+
+	reg.MustRegister(m.DeviceErrorCount)
+	reg.MustRegister(m.DeviceErrorPublishErr)
+	reg.MustRegister(m.UnexpectedDeviceErrorNotificationErr)
+
 	reg.MustRegister(m.NotificationPublishCount)
 	reg.MustRegister(m.NotificationPublishLatencyMs)
 	reg.MustRegister(m.NotificationPublishErr)
@@ -335,6 +739,9 @@ func NewMetrics(reg prometheus.Registerer) *Metrics {
 	reg.MustRegister(m.ProcessInstanceDurationMs)
 	reg.MustRegister(m.ProcessPreparedDeploymentErr)
 	reg.MustRegister(m.ProcessUnexpectedPreparedDeploymentSelectablesErr)
+	reg.MustRegister(m.ProcessMissingIncidentErr)
+	reg.MustRegister(m.ProcessUnexpectedResponseValueErr)
+	reg.MustRegister(m.DeviceSimulatorDroppedResponseCount)
 
 	reg.MustRegister(m.EventProcessDeploymentErr)
 	reg.MustRegister(m.UnexpectedEventProcessInstanceStateErr)
@@ -25,6 +25,23 @@ type ProcessInstance struct {
 	State                 string `json:"state"`
 }
 
+type VariableInstance struct {
+	Id                string      `json:"id"`
+	Name              string      `json:"name"`
+	Type              string      `json:"type"`
+	Value             interface{} `json:"value"`
+	ProcessInstanceId string      `json:"processInstanceId"`
+}
+
+type Incident struct {
+	Id                string `json:"id"`
+	ExternalTaskId    string `json:"external_task_id"`
+	ProcessInstanceId string `json:"process_instance_id"`
+	WorkerId          string `json:"worker_id"`
+	ErrorMessage      string `json:"error_message"`
+	Time              string `json:"time"`
+}
+
 type PreparedDeployment struct {
 	Id       string    `json:"id"`
 	Name     string    `json:"name"`
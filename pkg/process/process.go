@@ -17,7 +17,9 @@
 package process
 
 import (
+	"encoding/json"
 	"errors"
+	"reflect"
 	"sync/atomic"
 	"time"
 
@@ -33,6 +35,9 @@ type Process struct {
 	guaranteeChangeAfter time.Duration
 	receivedCommands     atomic.Int64
 	metrics              *metrics.Metrics
+	scenario             string
+	responseValue        atomic.Pointer[string]
+	droppedResponse      atomic.Bool
 }
 
 type DeviceInfo = devicemetadata.DeviceInfo
@@ -50,8 +55,13 @@ func (this *Process) getChangeGuaranteeDuration() time.Duration {
 	return this.guaranteeChangeAfter
 }
 
-func (this *Process) ProcessStartup(token string, info DeviceInfo) error {
+// ProcessStartup deploys and starts the canary process.
+// scenario describes how the canary device answers the command; it is used by ProcessTeardown to evaluate the process instance.
+func (this *Process) ProcessStartup(token string, info DeviceInfo, scenario string) error {
 	this.receivedCommands.Store(0)
+	this.responseValue.Store(nil)
+	this.droppedResponse.Store(false)
+	this.scenario = scenario
 	ids, err := this.ListCanaryProcessDeployments(token)
 	if err != nil {
 		this.metrics.UncategorizedErr.Inc()
@@ -162,12 +172,18 @@ func (this *Process) ProcessTeardown(token string) error {
 		if len(instances) != 1 {
 			this.metrics.UncategorizedErr.Inc()
 			this.config.GetLogger().Error("unexpected process instance list count", "count", len(instances))
+		} else if this.scenario != configuration.CommandScenarioSuccess || this.droppedResponse.Load() {
+			//responses dropped by the device simulator are handled like the no_response scenario
+			this.checkIncident(token, instances[0].Id)
 		} else {
 			if instances[0].State != "COMPLETED" {
 				this.metrics.UnexpectedProcessInstanceStateErr.Inc()
 				this.config.GetLogger().Error("unexpected process instance state", "state", instances[0].State)
 			} else {
 				this.metrics.ProcessInstanceDurationMs.Set(float64(instances[0].DurationInMillis))
+				if value := this.responseValue.Load(); value != nil {
+					this.checkResponseValue(token, instances[0].Id, *value)
+				}
 			}
 		}
 	}
@@ -189,6 +205,103 @@ func (this *Process) ProcessTeardown(token string) error {
 	return nil
 }
 
+// checkIncident expects an incident for the process instance and that the instance does not complete.
+// the incident is created by the command worker after a command error or a timeout; the check waits up to ProcessIncidentTimeout.
+func (this *Process) checkIncident(token string, instanceId string) {
+	timeout, err := time.ParseDuration(this.config.ProcessIncidentTimeout)
+	if err != nil {
+		this.metrics.UncategorizedErr.Inc()
+		this.config.GetLogger().Error("invalid process_incident_timeout", "error", err)
+		return
+	}
+	start := time.Now()
+	for {
+		incidents, err := this.GetIncidents(token, instanceId)
+		if err != nil {
+			this.metrics.UncategorizedErr.Inc()
+			this.config.GetLogger().Error("unable to get process incidents", "error", err)
+			return
+		}
+		if len(incidents) > 0 {
+			break
+		}
+		if time.Since(start) > timeout {
+			this.metrics.ProcessMissingIncidentErr.Inc()
+			this.config.GetLogger().Error("missing process incident", "scenario", this.scenario, "instance", instanceId)
+			return
+		}
+		time.Sleep(this.getChangeGuaranteeDuration())
+	}
+
+	instances, err := this.GetProcessInstances(token)
+	if err != nil {
+		this.metrics.UncategorizedErr.Inc()
+		this.config.GetLogger().Error("unable to get process instances", "error", err)
+		return
+	}
+	for _, instance := range instances {
+		if instance.Id == instanceId && instance.State == "COMPLETED" {
+			this.metrics.UnexpectedProcessInstanceStateErr.Inc()
+			this.config.GetLogger().Error("unexpected process instance state", "state", instance.State, "scenario", this.scenario)
+		}
+	}
+}
+
+// checkResponseValue expects the value of the command response in the result variable of the process instance
+func (this *Process) checkResponseValue(token string, instanceId string, value string) {
+	variables, err := this.GetVariableInstances(token, instanceId)
+	if err != nil {
+		this.metrics.UncategorizedErr.Inc()
+		this.config.GetLogger().Error("unable to get process variables", "error", err)
+		return
+	}
+	for _, variable := range variables {
+		if variable.Name != this.config.GetProcessResultVariableName() {
+			continue
+		}
+		if !responseValueMatches(variable.Value, value) {
+			this.metrics.ProcessUnexpectedResponseValueErr.Inc()
+			this.config.GetLogger().Error("unexpected command response value in process variable", "expected", value, "actual", variable.Value, "variable", variable.Name, "instance", instanceId)
+		}
+		return
+	}
+	this.metrics.ProcessUnexpectedResponseValueErr.Inc()
+	this.config.GetLogger().Error("missing process result variable", "variable", this.config.GetProcessResultVariableName(), "instance", instanceId)
+}
+
+// responseValueMatches compares the decoded process variable with the json encoded response value.
+// numbers are decoded as float64 and may not be formatted like the response (e.g. 1e+06); they are compared as decoded json.
+func responseValueMatches(actual interface{}, expected string) bool {
+	if str, ok := actual.(string); ok {
+		return str == expected
+	}
+	var expectedValue interface{}
+	err := json.Unmarshal([]byte(expected), &expectedValue)
+	if err != nil {
+		return false
+	}
+	temp, err := json.Marshal(actual)
+	if err != nil {
+		return false
+	}
+	var actualValue interface{}
+	err = json.Unmarshal(temp, &actualValue)
+	if err != nil {
+		return false
+	}
+	return reflect.DeepEqual(actualValue, expectedValue)
+}
+
 func (this *Process) NotifyCommand(topic string, payload []byte) {
 	this.receivedCommands.Add(1)
 }
+
+// NotifyCommandDropped is called if the device simulator drops the response to a command
+func (this *Process) NotifyCommandDropped() {
+	this.droppedResponse.Store(true)
+}
+
+// NotifyCommandResponse is called before the canary device publishes the response value to a command
+func (this *Process) NotifyCommandResponse(value string) {
+	this.responseValue.Store(&value)
+}
@@ -0,0 +1,37 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package process
+
+import "testing"
+
+func TestResponseValueMatches(t *testing.T) {
+	for _, c := range []struct {
+		actual   interface{}
+		expected string
+		matches  bool
+	}{
+		{actual: float64(1000000), expected: "1000000", matches: true},
+		{actual: float64(42), expected: "42", matches: true},
+		{actual: "42", expected: "42", matches: true},
+		{actual: float64(41), expected: "42", matches: false},
+		{actual: nil, expected: "42", matches: false},
+	} {
+		if responseValueMatches(c.actual, c.expected) != c.matches {
+			t.Error("unexpected result", c.actual, c.expected, c.matches)
+		}
+	}
+}
@@ -204,6 +204,60 @@ func (this *Process) GetProcessInstances(token string) (result []ProcessInstance
 	return result, nil
 }
 
+func (this *Process) GetVariableInstances(token string, processInstanceId string) (result []VariableInstance, err error) {
+	endpoint := this.config.ProcessEngineWrapperUrl + "/v2/history/variable-instances?" + url.Values{"processInstanceId": {processInstanceId}}.Encode()
+	method := "GET"
+
+	req, err := http.NewRequest(method, endpoint, nil)
+	if err != nil {
+		return result, err
+	}
+	req.Header.Set("Authorization", token)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return result, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode > 299 {
+		temp, _ := io.ReadAll(resp.Body) //read error response end ensure that resp.Body is read to EOF
+		return result, errors.New("unable to list process variables: " + string(temp))
+	}
+	err = json.NewDecoder(resp.Body).Decode(&result)
+	if err != nil {
+		_, _ = io.ReadAll(resp.Body) //ensure resp.Body is read to EOF
+		return result, err
+	}
+	return result, nil
+}
+
+func (this *Process) GetIncidents(token string, processInstanceId string) (result []Incident, err error) {
+	endpoint := this.config.ProcessEngineWrapperUrl + "/v2/incidents?" + url.Values{"process_instance_id": {processInstanceId}}.Encode()
+	method := "GET"
+
+	req, err := http.NewRequest(method, endpoint, nil)
+	if err != nil {
+		return result, err
+	}
+	req.Header.Set("Authorization", token)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return result, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode > 299 {
+		temp, _ := io.ReadAll(resp.Body) //read error response end ensure that resp.Body is read to EOF
+		return result, errors.New("unable to list process incidents: " + string(temp))
+	}
+	err = json.NewDecoder(resp.Body).Decode(&result)
+	if err != nil {
+		_, _ = io.ReadAll(resp.Body) //ensure resp.Body is read to EOF
+		return result, err
+	}
+	return result, nil
+}
+
 //go:embed canary_process.bpmn
 var ProcessBpmn string
 
@@ -25,7 +25,7 @@ import (
 )
 
 func Start(ctx context.Context, wg *sync.WaitGroup, config configuration.Config) error {
-	cmd, err := canary.New(ctx, wg, config)
+	cmd, err := canary.NewCanaries(ctx, wg, config)
 	if err != nil {
 		return err
 	}